@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/routes"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+)
+
+// routes command, prints the registered routes as a table or json
+func printRoutes(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("routes", flag.ContinueOnError)
+	format := flags.String("format", "table", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// gin debug messages go to stdout
+	gin.SetMode(gin.ReleaseMode)
+
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+
+	infos, err := routes.Describe(routes.Options{Config: config.Static(cfg), Metrics: true}, served()...)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
+	case "table":
+		writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "METHOD\tPATH\tVERSION\tHANDLERS\tMIDDLEWARE")
+		for _, info := range infos {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+				info.Method,
+				info.Path,
+				info.Version,
+				strings.Join(info.Handlers, " → "),
+				strings.Join(info.Middleware, ", "),
+			)
+		}
+		return writer.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// openapi command, writes the OpenAPI document to a file or to out
+func writeOpenAPI(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	output := flags.String("o", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(routes.OpenAPI(modules...))
+}
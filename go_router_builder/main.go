@@ -1,7 +1,73 @@
 package main
 
-import "github.com/nmarsollier/go_router_builder/rest/routes"
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/rest/routes"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"github.com/nmarsollier/go_router_builder/utils/health"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
+)
+
+var (
+	hooks  = &shutdown.Hooks{}
+	checks = health.NewRegistry(2 * time.Second)
+)
+
+var modules = []routes.Routes{
+	routes.API,
+	routes.Health(checks, hooks),
+}
 
 func main() {
-	routes.Start()
+	if len(os.Args) > 1 && os.Args[1] == "routes" {
+		exitOnError(printRoutes(os.Args[2:], os.Stdout))
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		exitOnError(writeOpenAPI(os.Args[2:], os.Stdout))
+		return
+	}
+
+	store, err := config.NewStore(os.Args[1:])
+	exitOnError(err)
+
+	addChecks(store)
+	routes.Start(store, hooks, served()...)
+}
+
+// The services we depend on must be reachable to be ready
+func addChecks(store *config.Store) {
+	checks.Add(health.Check{
+		Name:     "user service",
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			return health.Reachable(ctx, store.Current().UserURL)
+		},
+	})
+	checks.Add(health.Check{
+		Name:     "profile service",
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			return health.Reachable(ctx, store.Current().ProfileURL)
+		},
+	})
+}
+
+// served are the modules and the OpenAPI document that describes them
+func served() []routes.Routes {
+	return append(append([]routes.Routes{}, modules...), routes.Docs(modules...))
+}
+
+func exitOnError(err error) {
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 }
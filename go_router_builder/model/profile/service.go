@@ -1,6 +1,12 @@
 package profile
 
-import "time"
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/client"
+)
 
 // Profile data
 type Profile struct {
@@ -9,14 +15,26 @@ type Profile struct {
 	Web   string
 }
 
-// FetchProfile Devuelve información de Usuario
-func FetchProfile() *Profile {
-	// Un delay para simular tiempo de espera en llamadas remotas
-	time.Sleep(1 * time.Second)
+// FetchProfile Devuelve información de Usuario, si no hay serviceURL se simula la llamada remota
+func FetchProfile(ctx context.Context, serviceURL string, id string) (*Profile, error) {
+	if serviceURL == "" {
+		// Un delay para simular tiempo de espera en llamadas remotas
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		return &Profile{
+			Login: "nmarsollier",
+			Name:  "Nestor Marsollier",
+			Web:   "https://github.com/nmarsollier/profile",
+		}, nil
+	}
 
-	return &Profile{
-		Login: "nmarsollier",
-		Name:  "Nestor Marsollier",
-		Web:   "https://github.com/nmarsollier/profile",
+	result := &Profile{}
+	if err := client.GetJSON(ctx, serviceURL+"/"+url.PathEscape(id), result); err != nil {
+		return nil, err
 	}
+	return result, nil
 }
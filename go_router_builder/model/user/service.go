@@ -1,6 +1,13 @@
 package user
 
-import "time"
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/client"
+)
 
 // User data
 type User struct {
@@ -8,13 +15,35 @@ type User struct {
 	Access string
 }
 
-// FetchUser Devuelve información de Usuario
-func FetchUser() *User {
-	// Un delay para simular tiempo de espera en llamadas remotas
-	time.Sleep(1 * time.Second)
+// HasRole checks if role is one of the comma separated roles of Access
+func (u *User) HasRole(role string) bool {
+	for _, value := range strings.Split(u.Access, ",") {
+		if strings.TrimSpace(value) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchUser Devuelve información de Usuario, si no hay serviceURL se simula la llamada remota
+func FetchUser(ctx context.Context, serviceURL string, id string) (*User, error) {
+	if serviceURL == "" {
+		// Un delay para simular tiempo de espera en llamadas remotas
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		return &User{
+			Login:  "nmarsollier",
+			Access: "ADMIN,USER",
+		}, nil
+	}
 
-	return &User{
-		Login:  "nmarsollier",
-		Access: "ADMIN,USER",
+	result := &User{}
+	if err := client.GetJSON(ctx, serviceURL+"/"+url.PathEscape(id), result); err != nil {
+		return nil, err
 	}
+	return result, nil
 }
@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+)
+
+// Acceptable answers 406 before running the handlers when the client accepts none of the
+// media types responses are rendered in, so the request does not use rate limits or upstreams
+func Acceptable(c *gin.Context) {
+	if !gu.IsAcceptable(c) {
+		gu.NotAcceptable(c)
+		return
+	}
+
+	c.Next()
+}
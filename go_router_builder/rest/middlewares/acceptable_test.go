@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestAcceptable(t *testing.T) {
+	calls := 0
+	engine := gin.New()
+	engine.Use(Acceptable)
+	engine.GET("/users/:id", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"login": "jdoe"})
+	})
+
+	get := func(accept string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/users/123", nil)
+		request.Header.Set("Accept", accept)
+		request.Header.Set("Accept-Language", "en")
+		engine.ServeHTTP(response, request)
+		return response
+	}
+
+	response := get("image/png")
+	assert.Equal(t, response.Code, http.StatusNotAcceptable)
+	assert.Equal(t, response.Body.String(), `{"error":"None of the accepted formats is available"}`)
+	assert.Equal(t, calls, 0)
+
+	assert.Equal(t, get("application/json").Code, http.StatusOK)
+	assert.Equal(t, get("image/png, */*;q=0.1").Code, http.StatusOK)
+	assert.Equal(t, calls, 2)
+}
@@ -0,0 +1,186 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDKey is the gin context key of the authenticated user id
+const UserIDKey = "user_id"
+
+// Redacted replaces the values of redacted query parameters and headers
+const Redacted = "[REDACTED]"
+
+// AccessLogSampling chooses the successful requests logged
+type AccessLogSampling struct {
+	// Sample is the fraction of successful requests logged, errors and slow requests are always logged
+	Sample float64
+	// Slow requests are logged as warnings, zero disables it
+	Slow time.Duration
+	// Level is the lowest level logged: info, warn or error. Info when empty
+	Level string
+}
+
+// AccessLogOptions configures the access log
+type AccessLogOptions struct {
+	// Output receives a json line per request, stdout when nil
+	Output io.Writer
+	// Sampling is read on each request, so it can change while serving. Every request
+	// is logged when nil
+	Sampling    func() AccessLogSampling
+	RedactQuery []string
+	// Headers are the request headers logged, others are left out
+	Headers       []string
+	RedactHeaders []string
+}
+
+type accessEntry struct {
+	Time      string            `json:"time"`
+	Level     string            `json:"level"`
+	Method    string            `json:"method"`
+	Route     string            `json:"route"`
+	Path      string            `json:"path"`
+	Status    int               `json:"status"`
+	LatencyMs float64           `json:"latency_ms"`
+	Bytes     int               `json:"bytes"`
+	ClientIP  string            `json:"client_ip"`
+	RequestID string            `json:"request_id,omitempty"`
+	UserID    string            `json:"user_id,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Errors    []string          `json:"errors,omitempty"`
+}
+
+var random = rand.Float64
+
+// The access log levels, from the lowest
+var levels = map[string]int{"info": 0, "warn": 1, "error": 2}
+
+// NewAccessLog logs a json object per request
+func NewAccessLog(options AccessLogOptions) gin.HandlerFunc {
+	output := options.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	mutex := &sync.Mutex{}
+
+	redactQuery := toSet(options.RedactQuery, strings.ToLower)
+	headers := toSet(options.Headers, http.CanonicalHeaderKey)
+	redactHeaders := toSet(options.RedactHeaders, http.CanonicalHeaderKey)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+
+		sampling := AccessLogSampling{Sample: 1}
+		if options.Sampling != nil {
+			sampling = options.Sampling()
+		}
+
+		level := "info"
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = "error"
+		case status >= http.StatusBadRequest || len(c.Errors) > 0:
+			level = "warn"
+		case sampling.Slow > 0 && latency >= sampling.Slow:
+			level = "warn"
+		case random() >= sampling.Sample:
+			return
+		}
+		if levels[level] < levels[sampling.Level] {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = UnmatchedRoute
+		}
+
+		entry := accessEntry{
+			Time:      start.UTC().Format(time.RFC3339Nano),
+			Level:     level,
+			Method:    c.Request.Method,
+			Route:     route,
+			Path:      redactedPath(c.Request.URL, redactQuery),
+			Status:    status,
+			LatencyMs: float64(latency) / float64(time.Millisecond),
+			Bytes:     c.Writer.Size(),
+			ClientIP:  c.ClientIP(),
+			RequestID: requestID(c),
+			UserID:    c.GetString(UserIDKey),
+			Headers:   redactedHeaders(c.Request.Header, headers, redactHeaders),
+			Errors:    c.Errors.Errors(),
+		}
+		if entry.Bytes < 0 {
+			entry.Bytes = 0
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("Error encoding access log : %s", err.Error())
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		output.Write(append(line, '\n'))
+	}
+}
+
+func redactedPath(u *url.URL, redact map[string]bool) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for name, values := range query {
+		if redact[strings.ToLower(name)] {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	return u.Path + "?" + query.Encode()
+}
+
+func redactedHeaders(headers http.Header, allowed, redact map[string]bool) map[string]string {
+	var result map[string]string
+	for name, values := range headers {
+		name = http.CanonicalHeaderKey(name)
+		if !allowed[name] {
+			continue
+		}
+
+		if result == nil {
+			result = map[string]string{}
+		}
+		if redact[name] {
+			result[name] = Redacted
+		} else {
+			result[name] = strings.Join(values, ", ")
+		}
+	}
+	return result
+}
+
+func toSet(values []string, normalize func(string) string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[normalize(value)] = true
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func accessLogEngine(options AccessLogOptions) *gin.Engine {
+	engine := gin.New()
+	engine.Use(RequestID, NewAccessLog(options))
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.Set(UserIDKey, "nmarsollier")
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/slow", func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+	})
+	engine.GET("/fail", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	return engine
+}
+
+func serveLogged(engine *gin.Engine, path string) {
+	request, _ := http.NewRequest("GET", path, nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("X-Request-ID", "abc-123")
+	request.Header.Set("Cookie", "session=secret")
+	engine.ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func TestAccessLog(t *testing.T) {
+	output := &bytes.Buffer{}
+	engine := accessLogEngine(AccessLogOptions{
+		Output:        output,
+		RedactQuery:   []string{"token"},
+		Headers:       []string{"authorization", "x-request-id"},
+		RedactHeaders: []string{"authorization"},
+	})
+
+	serveLogged(engine, "/users/123?token=secret&page=2")
+
+	entry := map[string]interface{}{}
+	assert.Equal(t, json.Unmarshal(output.Bytes(), &entry), nil)
+	assert.Equal(t, entry["level"], "info")
+	assert.Equal(t, entry["method"], "GET")
+	assert.Equal(t, entry["route"], "/users/:id")
+	assert.Equal(t, entry["path"], "/users/123?page=2&token=%5BREDACTED%5D")
+	assert.Equal(t, entry["status"], float64(200))
+	assert.Equal(t, entry["bytes"], float64(2))
+	assert.Equal(t, entry["request_id"], "abc-123")
+	assert.Equal(t, entry["user_id"], "nmarsollier")
+	assert.Equal(t, entry["headers"].(map[string]interface{})["Authorization"], Redacted)
+	assert.Equal(t, entry["headers"].(map[string]interface{})["X-Request-Id"], "abc-123")
+	assert.Equal(t, len(entry["headers"].(map[string]interface{})), 2)
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	random = func() float64 { return 0.5 }
+	defer func() { random = rand.Float64 }()
+
+	output := &bytes.Buffer{}
+	engine := accessLogEngine(AccessLogOptions{
+		Output: output,
+		Sampling: func() AccessLogSampling {
+			return AccessLogSampling{Sample: 0.1, Slow: time.Millisecond}
+		},
+	})
+
+	serveLogged(engine, "/users/123")
+	assert.Equal(t, output.Len(), 0)
+
+	serveLogged(engine, "/fail")
+	entry := map[string]interface{}{}
+	json.Unmarshal(output.Bytes(), &entry)
+	assert.Equal(t, entry["level"], "error")
+
+	output.Reset()
+	serveLogged(engine, "/slow")
+	json.Unmarshal(output.Bytes(), &entry)
+	assert.Equal(t, entry["level"], "warn")
+	assert.Equal(t, entry["route"], "/slow")
+}
+
+func TestAccessLogLevel(t *testing.T) {
+	level := "warn"
+	output := &bytes.Buffer{}
+	engine := accessLogEngine(AccessLogOptions{
+		Output: output,
+		Sampling: func() AccessLogSampling {
+			return AccessLogSampling{Sample: 1, Level: level}
+		},
+	})
+
+	serveLogged(engine, "/users/123")
+	assert.Equal(t, output.Len(), 0)
+
+	// Read on each request
+	level = "info"
+	serveLogged(engine, "/users/123")
+	entry := map[string]interface{}{}
+	json.Unmarshal(output.Bytes(), &entry)
+	assert.Equal(t, entry["level"], "info")
+
+	output.Reset()
+	level = "error"
+	serveLogged(engine, "/fail")
+	json.Unmarshal(output.Bytes(), &entry)
+	assert.Equal(t, entry["level"], "error")
+}
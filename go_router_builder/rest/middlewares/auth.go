@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/model/user"
+	"github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/utils/jwt"
+)
+
+// PrincipalKey is the gin context key of the authenticated *user.User
+const PrincipalKey = "principal"
+
+// realmKey is the gin context key of the realm sent in authentication challenges
+const realmKey = "auth_realm"
+
+// AuthOptions configures the authentication
+type AuthOptions struct {
+	Keys  *jwt.Keys
+	Realm string
+}
+
+// Authenticate verifies the bearer token of the request and stores the principal.
+// Requests without bearer token, or any request when there are no keys, go on anonymous,
+// routes that need a principal use RequireRoles
+func Authenticate(options AuthOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(realmKey, options.Realm)
+
+		// Other schemes, like Basic, may be for someone else, like a proxy.
+		// Schemes are case insensitive
+		credentials := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(credentials) != 2 || !strings.EqualFold(credentials[0], "Bearer") || options.Keys == nil {
+			c.Next()
+			return
+		}
+
+		claims, err := jwt.Verify(strings.TrimSpace(credentials[1]), options.Keys)
+		if err != nil {
+			unauthorized(c, err.Error())
+			return
+		}
+
+		principal := &user.User{
+			Login:  claims.Subject(),
+			Access: access(claims),
+		}
+		c.Set(PrincipalKey, principal)
+		c.Set(UserIDKey, principal.Login)
+
+		c.Next()
+	}
+}
+
+// RequireRoles answers 401 to anonymous requests and 403 to principals without all the roles
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := Principal(c)
+		if !ok {
+			unauthorized(c, "")
+			return
+		}
+
+		for _, role := range roles {
+			if !principal.HasRole(role) {
+				c.Error(errors.NewForbidden(roles...))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Principal is the authenticated user of the request
+func Principal(c *gin.Context) (*user.User, bool) {
+	value, ok := c.Get(PrincipalKey)
+	if !ok {
+		return nil, false
+	}
+	principal, ok := value.(*user.User)
+	return principal, ok
+}
+
+func unauthorized(c *gin.Context, reason string) {
+	err := errors.NewUnauthorized(c.GetString(realmKey))
+	if reason != "" {
+		err.WithMetadata("reason", reason)
+	}
+	c.Error(err)
+	c.Abort()
+}
+
+// Roles come as the access claim, "ADMIN,USER" like User.Access, or as a roles list
+func access(claims jwt.Claims) string {
+	if value, ok := claims["access"].(string); ok {
+		return value
+	}
+
+	list, _ := claims["roles"].([]interface{})
+	roles := make([]string, 0, len(list))
+	for _, role := range list {
+		if value, ok := role.(string); ok {
+			roles = append(roles, value)
+		}
+	}
+	return strings.Join(roles, ",")
+}
@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/jwt"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func hs256(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func authEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(ErrorHandler, Authenticate(AuthOptions{
+		Keys:  &jwt.Keys{Secret: []byte("secret")},
+		Realm: "test",
+	}))
+	engine.GET("/public", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(UserIDKey))
+	})
+	engine.GET("/admin", RequireRoles("ADMIN"), func(c *gin.Context) {
+		principal, _ := Principal(c)
+		c.String(http.StatusOK, principal.Login)
+	})
+	return engine
+}
+
+func serveAuth(path, token string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	request.Header.Set("Accept-Language", "en")
+	authEngine().ServeHTTP(response, request)
+	return response
+}
+
+func TestAuthenticate(t *testing.T) {
+	response := serveAuth("/public", "")
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "")
+
+	response = serveAuth("/public", hs256(map[string]interface{}{"sub": "nmarsollier"}))
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "nmarsollier")
+
+	response = httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/public", nil)
+	request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	authEngine().ServeHTTP(response, request)
+	assert.Equal(t, response.Code, 200)
+
+	response = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", "/public", nil)
+	request.Header.Set("Authorization", "bearer "+hs256(map[string]interface{}{"sub": "nmarsollier"}))
+	authEngine().ServeHTTP(response, request)
+	assert.Equal(t, response.Body.String(), "nmarsollier")
+
+	response = serveAuth("/public", "invalid")
+	assert.Equal(t, response.Code, 401)
+	assert.Equal(t, response.Header().Get("WWW-Authenticate"), `Bearer realm="test"`)
+	assert.Equal(t, response.Body.String(), `{"error":"Authentication required","metadata":{"reason":"malformed token"}}`)
+}
+
+func TestRequireRoles(t *testing.T) {
+	assert.Equal(t, serveAuth("/admin", "").Code, 401)
+
+	response := serveAuth("/admin", hs256(map[string]interface{}{"sub": "jdoe", "access": "USER"}))
+	assert.Equal(t, response.Code, 403)
+	assert.Equal(t, response.Body.String(),
+		`{"error":"You do not have permission for this operation","metadata":{"required_roles":["ADMIN"]}}`)
+
+	response = serveAuth("/admin", hs256(map[string]interface{}{"sub": "nmarsollier", "roles": []string{"ADMIN", "USER"}}))
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "nmarsollier")
+}
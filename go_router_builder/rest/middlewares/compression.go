@@ -0,0 +1,254 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/accept"
+)
+
+// DefaultCompressibleTypes are the media types compressed when no list is configured
+var DefaultCompressibleTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/yaml",
+	"application/x-yaml",
+	"text/plain",
+	"text/html",
+	"text/xml",
+	"text/yaml",
+}
+
+// CompressionOptions configures the response compression
+type CompressionOptions struct {
+	// MinSize is the smallest body compressed, smaller ones are not worth it
+	MinSize int
+	// ContentTypes are the media types compressed, DefaultCompressibleTypes when nil
+	ContentTypes []string
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var (
+	gzipPool = sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}}
+	// The deflate content coding is zlib wrapped (RFC 1950), not raw deflate
+	deflatePool = sync.Pool{New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(nil, zlib.DefaultCompression)
+		return w
+	}}
+	bufferPool = sync.Pool{New: func() interface{} {
+		return &bytes.Buffer{}
+	}}
+)
+
+// NewCompression compresses responses with gzip or deflate, as negotiated with Accept-Encoding.
+// Bodies are buffered up to MinSize to decide, responses that already have a
+// Content-Encoding or that are flushed, like streams, are sent as they are
+func NewCompression(options CompressionOptions) gin.HandlerFunc {
+	types := options.ContentTypes
+	if types == nil {
+		types = DefaultCompressibleTypes
+	}
+	allowed := toSet(types, strings.ToLower)
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       negotiateEncoding(c.GetHeader("Accept-Encoding")),
+			minSize:        options.MinSize,
+			allowed:        allowed,
+			buffer:         bufferPool.Get().(*bytes.Buffer),
+		}
+		c.Writer = writer
+
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// The first supported encoding accepted, "*" is any of them not refused with q=0
+func negotiateEncoding(header string) string {
+	refused := toSet(accept.Refused(header), strings.ToLower)
+
+	for _, encoding := range accept.Parse(header) {
+		switch encoding {
+		case "gzip", "deflate":
+			return encoding
+		case "*":
+			for _, supported := range []string{"gzip", "deflate"} {
+				if !refused[supported] {
+					return supported
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the body until it knows if it is worth compressing
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding   string
+	minSize    int
+	allowed    map[string]bool
+	buffer     *bytes.Buffer
+	decided    bool
+	compressor compressor
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) Written() bool {
+	return w.buffer != nil && w.buffer.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// WriteHeaderNow sends the headers, the body can not be compressed after that
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush is used by streams, that are sent without compression
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.compressor != nil {
+		w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.decided {
+		w.decide(false)
+	}
+	return w.ResponseWriter.Hijack()
+}
+
+// decide sets the headers and sends the buffered body, compressed when allowed
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(header.Get("Content-Type"), ";")[0]))
+	if w.allowed[mediaType] {
+		addVary(header, "Accept-Encoding")
+	}
+
+	compress = compress &&
+		w.encoding != "" &&
+		w.allowed[mediaType] &&
+		header.Get("Content-Encoding") == "" &&
+		w.buffer.Len() > 0 &&
+		w.buffer.Len() >= w.minSize &&
+		bodyAllowed(w.Status())
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		if w.encoding == "gzip" {
+			w.compressor = gzipPool.Get().(*gzip.Writer)
+		} else {
+			w.compressor = deflatePool.Get().(*zlib.Writer)
+		}
+		w.compressor.Reset(w.ResponseWriter)
+	}
+
+	buffered := w.buffer
+	w.buffer = nil
+	defer func() {
+		buffered.Reset()
+		bufferPool.Put(buffered)
+	}()
+
+	if buffered.Len() == 0 {
+		return nil
+	}
+	_, err := w.write(buffered.Bytes())
+	return err
+}
+
+// finish sends what is still buffered and returns the writers to the pools
+func (w *compressWriter) finish() {
+	if !w.decided {
+		// Bodies smaller than MinSize, or no body at all
+		w.decide(w.buffer.Len() > 0 && w.buffer.Len() >= w.minSize)
+	}
+
+	if w.compressor == nil {
+		return
+	}
+	w.compressor.Close()
+	switch compressor := w.compressor.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(compressor)
+	case *zlib.Writer:
+		deflatePool.Put(compressor)
+	}
+	w.compressor = nil
+}
+
+func addVary(header http.Header, value string) {
+	for _, vary := range header.Values("Vary") {
+		for _, v := range strings.Split(vary, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+var large = strings.Repeat("compressible ", 100)
+
+func compressionEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(NewCompression(CompressionOptions{MinSize: 512}))
+	engine.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": large})
+	})
+	engine.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": "small"})
+	})
+	engine.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+	engine.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "br")
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	engine.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain")
+		c.Writer.WriteString(large[:10])
+		c.Writer.Flush()
+		c.Writer.WriteString(large[10:])
+	})
+	return engine
+}
+
+func serveCompressed(path, acceptEncoding string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	request.Header.Set("Accept-Encoding", acceptEncoding)
+	compressionEngine().ServeHTTP(response, request)
+	return response
+}
+
+func TestGzip(t *testing.T) {
+	response := serveCompressed("/large", "deflate;q=0.5, gzip")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "gzip")
+	assert.Equal(t, response.Header().Get("Vary"), "Accept-Encoding")
+
+	reader, err := gzip.NewReader(response.Body)
+	assert.Equal(t, err, nil)
+	body, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, string(body), `{"text":"`+large+`"}`)
+}
+
+func TestDeflate(t *testing.T) {
+	response := serveCompressed("/large", "deflate, gzip;q=0.5")
+
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "deflate")
+	reader, err := zlib.NewReader(response.Body)
+	assert.Equal(t, err, nil)
+	body, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, string(body), `{"text":"`+large+`"}`)
+}
+
+func TestAnyEncoding(t *testing.T) {
+	assert.Equal(t, serveCompressed("/large", "*").Header().Get("Content-Encoding"), "gzip")
+	assert.Equal(t, serveCompressed("/large", "gzip;q=0, *").Header().Get("Content-Encoding"), "deflate")
+	assert.Equal(t, serveCompressed("/large", "gzip;q=0, deflate;q=0, *").Header().Get("Content-Encoding"), "")
+}
+
+func TestNotCompressed(t *testing.T) {
+	response := serveCompressed("/large", "")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Header().Get("Vary"), "Accept-Encoding")
+	assert.Equal(t, response.Body.String(), `{"text":"`+large+`"}`)
+
+	response = serveCompressed("/small", "gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Body.String(), `{"text":"small"}`)
+
+	response = serveCompressed("/image", "gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Header().Get("Vary"), "")
+	assert.Equal(t, response.Body.String(), large)
+
+	response = serveCompressed("/encoded", "gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "br")
+	assert.Equal(t, response.Body.String(), large)
+
+	response = serveCompressed("/stream", "gzip")
+	assert.Equal(t, response.Header().Get("Content-Encoding"), "")
+	assert.Equal(t, response.Body.String(), large)
+}
@@ -1,9 +1,16 @@
 package middlewares
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+	"github.com/nmarsollier/go_router_builder/utils/report"
+	"github.com/nmarsollier/go_router_builder/utils/uuid"
 )
 
 // ICustomError define un error con Code y Error
@@ -12,6 +19,34 @@ type ICustomError interface {
 	Error() string
 }
 
+// ILocalizedError define un error cuyo mensaje se resuelve con i18n
+type ILocalizedError interface {
+	MessageKey() string
+	MessageParams() map[string]interface{}
+}
+
+// IHeadersError define un error que agrega headers a la respuesta
+type IHeadersError interface {
+	Headers() http.Header
+}
+
+// IMetadataError define un error que agrega valores al body de la respuesta
+type IMetadataError interface {
+	Metadata() map[string]interface{}
+}
+
+// ErrorPolicy decides which of the context errors are sent to the client
+type ErrorPolicy int
+
+const (
+	// HighestStatus reports the error with the highest http status
+	HighestStatus ErrorPolicy = iota
+	// FirstError reports the first error added to the context
+	FirstError
+	// AllErrors reports every error in a list
+	AllErrors
+)
+
 // ErrorHandler a middleware to handle errors
 func ErrorHandler(c *gin.Context) {
 	c.Next()
@@ -19,26 +54,217 @@ func ErrorHandler(c *gin.Context) {
 	handleErrorIfNeeded(c)
 }
 
+// ErrorOptions configures the error middleware
+type ErrorOptions struct {
+	Policy   ErrorPolicy
+	Reporter report.Reporter
+	// Production only sends ICustomError messages to clients, other errors are
+	// logged with a correlation id that is the only detail the client gets
+	Production bool
+}
+
+// NewErrorHandler a middleware to handle errors using the given options
+func NewErrorHandler(options ErrorOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		handleErrors(c, options)
+	}
+}
+
 func handleErrorIfNeeded(c *gin.Context) {
-	err := c.Errors.Last()
-	if err == nil {
+	handleErrors(c, ErrorOptions{})
+}
+
+type resolvedError struct {
+	status   int
+	message  string
+	err      error
+	redacted bool
+	headers  http.Header
+	metadata map[string]interface{}
+}
+
+func handleErrors(c *gin.Context, options ErrorOptions) {
+	if len(c.Errors) == 0 {
 		return
 	}
 
-	switch value := err.Err.(type) {
-	case ICustomError:
-		c.JSON(value.Code(),
-			gin.H{
-				"error": value.Error(),
-			})
-	case error:
-		c.JSON(http.StatusInternalServerError,
-			gin.H{
-				"error": value.Error(),
-			})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err,
-		})
+	lang := i18n.Negotiate(acceptLanguage(c))
+
+	resolved := make([]resolvedError, len(c.Errors))
+	for i, err := range c.Errors {
+		resolved[i] = resolveError(lang, err.Err)
+	}
+
+	correlationID := ""
+	if options.Production {
+		correlationID = redactErrors(c, lang, resolved)
+	}
+
+	if options.Reporter != nil {
+		reportErrors(c, options.Reporter, resolved)
+	}
+
+	if options.Policy == AllErrors {
+		status := 0
+		messages := make([]string, len(resolved))
+		for i, value := range resolved {
+			if value.status > status {
+				status = value.status
+			}
+			messages[i] = value.message
+		}
+
+		writeHeaders(c, resolved...)
+		gu.Render(c, status, withCorrelationID(c, gin.H{
+			"errors": messages,
+		}, correlationID))
+		return
+	}
+
+	selected := 0
+	if options.Policy == HighestStatus {
+		for i, value := range resolved {
+			if value.status > resolved[selected].status {
+				selected = i
+			}
+		}
+	}
+
+	for i, value := range resolved {
+		if i != selected {
+			log.Printf("Error not reported to client %s : %d %s", requestPath(c), value.status, value.err.Error())
+		}
+	}
+
+	body := gin.H{
+		"error": resolved[selected].message,
+	}
+	if len(resolved[selected].metadata) > 0 {
+		body["metadata"] = gin.H(resolved[selected].metadata)
+	}
+
+	writeHeaders(c, resolved[selected])
+	gu.Render(c, resolved[selected].status, withCorrelationID(c, body, correlationID))
+}
+
+func resolveError(lang string, err error) resolvedError {
+	var custom ICustomError
+	if errors.As(err, &custom) {
+		result := resolvedError{status: custom.Code(), message: localize(lang, custom), err: err}
+
+		var headers IHeadersError
+		if errors.As(err, &headers) {
+			result.headers = headers.Headers()
+		}
+		var metadata IMetadataError
+		if errors.As(err, &metadata) {
+			result.metadata = metadata.Metadata()
+		}
+		return result
+	}
+
+	status, ok := mappedStatus(err)
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	return resolvedError{status: status, message: localize(lang, err), err: err, redacted: true}
+}
+
+func writeHeaders(c *gin.Context, resolved ...resolvedError) {
+	for _, value := range resolved {
+		for key, values := range value.headers {
+			for _, v := range values {
+				c.Writer.Header().Add(key, v)
+			}
+		}
+	}
+}
+
+// Replaces the messages of non custom errors with a generic one, and logs them
+// with a correlation id
+func redactErrors(c *gin.Context, lang string, resolved []resolvedError) string {
+	correlationID := ""
+	for i, value := range resolved {
+		if !value.redacted {
+			continue
+		}
+
+		if correlationID == "" {
+			correlationID = uuid.New()
+		}
+		log.Printf("Error %s, correlation id %s : %d %s", requestPath(c), correlationID, value.status, value.err.Error())
+
+		key := i18n.RequestError
+		if value.status >= http.StatusInternalServerError {
+			key = i18n.InternalServerError
+		}
+		resolved[i].message = i18n.Translate(lang, key, nil)
+	}
+	return correlationID
+}
+
+func withCorrelationID(c *gin.Context, body gin.H, correlationID string) gin.H {
+	if correlationID != "" {
+		body["correlation_id"] = correlationID
+	}
+	if id := requestID(c); id != "" {
+		body["request_id"] = id
+	}
+	return body
+}
+
+func reportErrors(c *gin.Context, reporter report.Reporter, resolved []resolvedError) {
+	for _, value := range resolved {
+		sendReport(c, reporter, value.status, value.err.Error())
+	}
+}
+
+// sendReport reports an error of the request
+func sendReport(c *gin.Context, reporter report.Reporter, status int, message string) {
+	event := report.Event{
+		Time:      time.Now(),
+		Status:    status,
+		Message:   message,
+		RequestID: requestID(c),
+	}
+	if c.Request != nil {
+		event.Method = c.Request.Method
+		event.Path = c.FullPath()
+		if event.Path == "" {
+			event.Path = c.Request.URL.Path
+		}
+	}
+
+	if err := reporter.Report(event); err != nil {
+		log.Printf("Error reporting error : %s", err.Error())
+	}
+}
+
+func localize(lang string, err error) string {
+	var value ILocalizedError
+	if errors.As(err, &value) && value.MessageKey() != "" {
+		return i18n.Translate(lang, value.MessageKey(), value.MessageParams())
+	}
+	return err.Error()
+}
+
+func acceptLanguage(c *gin.Context) string {
+	if c.Request == nil {
+		return ""
+	}
+	return c.GetHeader("Accept-Language")
+}
+
+// requestPath identifies the request in logs
+func requestPath(c *gin.Context) string {
+	if c.Request == nil {
+		return ""
+	}
+	if id := requestID(c); id != "" {
+		return "[" + id + "] " + c.Request.Method + " " + c.Request.URL.Path
 	}
+	return c.Request.Method + " " + c.Request.URL.Path
 }
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	errutils "github.com/nmarsollier/go_router_builder/utils/errors"
+)
+
+type errorMapping struct {
+	target error
+	status int
+}
+
+var mappingsMutex = &sync.RWMutex{}
+
+var errorMappings = []errorMapping{
+	{errutils.ErrNotFound, http.StatusNotFound},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+}
+
+// MapError registers the http status returned for errors matching target with errors.Is
+// so services and DAOs can return plain errors
+func MapError(target error, status int) {
+	mappingsMutex.Lock()
+	defer mappingsMutex.Unlock()
+
+	for i, mapping := range errorMappings {
+		if mapping.target == target {
+			errorMappings[i].status = status
+			return
+		}
+	}
+	errorMappings = append(errorMappings, errorMapping{target, status})
+}
+
+func mappedStatus(err error) (int, bool) {
+	mappingsMutex.RLock()
+	defer mappingsMutex.RUnlock()
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.target) {
+			return mapping.status, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,243 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	errutils "github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/utils/report"
+	"github.com/nmarsollier/go_router_builder/utils/requestid"
+	"github.com/nmarsollier/go_router_builder/utils/test"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestCustomError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(errutils.NewCustomError(400, "Custom Test"))
+	handleErrorIfNeeded(context)
+
+	response.Assert(400, "{\"error\":\"Custom Test\"}")
+}
+
+func TestError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(errors.New("Error Test"))
+	handleErrorIfNeeded(context)
+
+	response.Assert(500, "{\"error\":\"Error Test\"}")
+}
+
+func TestLocalizedError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/", nil)
+	context.Request.Header.Set("Accept-Language", "fr, en-US;q=0.8, es;q=0.5")
+
+	context.Error(errutils.NewLocalizedError(400, "min_length", map[string]interface{}{"param": "id", "min": 1}))
+	handleErrorIfNeeded(context)
+
+	response.Assert(400, "{\"error\":\"id must have at least 1 characters\"}")
+}
+
+func TestLocalizedErrorFallback(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/", nil)
+
+	context.Error(errutils.NewLocalizedError(500, "internal_server_error", nil))
+	handleErrorIfNeeded(context)
+
+	response.Assert(500, "{\"error\":\"Error interno del servidor\"}")
+}
+
+func TestWrappedCustomError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(fmt.Errorf("fetching user: %w", errutils.NewCustomError(400, "Custom Test")))
+	handleErrorIfNeeded(context)
+
+	response.Assert(400, "{\"error\":\"Custom Test\"}")
+}
+
+func TestMappedError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(fmt.Errorf("user 123: %w", errutils.ErrNotFound))
+	handleErrorIfNeeded(context)
+
+	response.Assert(404, "{\"error\":\"user 123: not found\"}")
+}
+
+func TestDeadlineExceeded(t *testing.T) {
+	response := test.ResponseWriter(t)
+	c, _ := gin.CreateTestContext(response)
+
+	c.Error(fmt.Errorf("fetching profile: %w", context.DeadlineExceeded))
+	handleErrorIfNeeded(c)
+
+	response.Assert(504, "{\"error\":\"fetching profile: context deadline exceeded\"}")
+}
+
+func TestMapError(t *testing.T) {
+	errConflict := errors.New("conflict")
+	MapError(errConflict, 409)
+
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(errConflict)
+	handleErrorIfNeeded(context)
+
+	response.Assert(409, "{\"error\":\"conflict\"}")
+}
+
+func TestErrorPolicies(t *testing.T) {
+	addErrors := func(c *gin.Context) {
+		c.Error(errutils.NewCustomError(400, "Bad Request"))
+		c.Error(errors.New("Error Test"))
+		c.Error(fmt.Errorf("profile: %w", errutils.ErrNotFound))
+	}
+
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	addErrors(context)
+	handleErrors(context, ErrorOptions{Policy: HighestStatus})
+	response.Assert(500, "{\"error\":\"Error Test\"}")
+
+	response = test.ResponseWriter(t)
+	context, _ = gin.CreateTestContext(response)
+	addErrors(context)
+	handleErrors(context, ErrorOptions{Policy: FirstError})
+	response.Assert(400, "{\"error\":\"Bad Request\"}")
+
+	response = test.ResponseWriter(t)
+	context, _ = gin.CreateTestContext(response)
+	addErrors(context)
+	handleErrors(context, ErrorOptions{Policy: AllErrors})
+	response.Assert(500, "{\"errors\":[\"Bad Request\",\"Error Test\",\"profile: not found\"]}")
+}
+
+func TestErrorReporter(t *testing.T) {
+	var events []report.Event
+	reporter := report.ReporterFunc(func(event report.Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request = context.Request.WithContext(requestid.NewContext(context.Request.Context(), "req-1"))
+
+	context.Error(errutils.NewCustomError(400, "Bad Request"))
+	context.Error(errors.New("Error Test"))
+	handleErrors(context, ErrorOptions{Reporter: reporter})
+
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Status, 400)
+	assert.Equal(t, events[1].Message, "Error Test")
+	assert.Equal(t, events[1].RequestID, "req-1")
+	assert.Equal(t, events[1].Path, "/users/123")
+}
+
+func TestProductionRedaction(t *testing.T) {
+	response := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request.Header.Set("Accept-Language", "en")
+
+	context.Error(errors.New("dial tcp db.internal:5432: connection refused"))
+	context.Error(errutils.NewCustomError(400, "Bad Request"))
+	handleErrors(context, ErrorOptions{Policy: AllErrors, Production: true})
+
+	body := map[string]interface{}{}
+	json.Unmarshal(response.Body.Bytes(), &body)
+
+	assert.Equal(t, response.Code, 500)
+	assert.Equal(t, body["errors"], []interface{}{"Internal Server Error", "Bad Request"})
+	assert.Equal(t, len(body["correlation_id"].(string)), 36)
+}
+
+func TestDevelopmentError(t *testing.T) {
+	response := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request.Header.Set("Accept-Language", "es")
+
+	context.Error(errors.New("dial tcp db:5432 refused"))
+	handleErrors(context, ErrorOptions{})
+
+	assert.Equal(t, response.Code, 500)
+	assert.Equal(t, response.Body.String(), "{\"error\":\"dial tcp db:5432 refused\"}")
+
+	response = httptest.NewRecorder()
+	context, _ = gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request.Header.Set("Accept-Language", "es")
+
+	context.Error(errors.New("dial tcp db:5432 refused"))
+	handleErrors(context, ErrorOptions{Production: true})
+
+	body := map[string]interface{}{}
+	json.Unmarshal(response.Body.Bytes(), &body)
+	assert.Equal(t, body["error"], "Error interno del servidor")
+}
+
+func TestProductionCustomError(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+
+	context.Error(errutils.NewCustomError(400, "Custom Test"))
+	handleErrors(context, ErrorOptions{Production: true})
+
+	response.Assert(400, "{\"error\":\"Custom Test\"}")
+}
+
+func TestErrorHeaders(t *testing.T) {
+	response := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request.Header.Set("Accept-Language", "en")
+
+	context.Error(errutils.NewRateLimited(1500 * time.Millisecond))
+	handleErrorIfNeeded(context)
+
+	assert.Equal(t, response.Code, 429)
+	assert.Equal(t, response.Header().Get("Retry-After"), "2")
+	assert.Equal(t, response.Body.String(), "{\"error\":\"Too many requests, retry later\",\"metadata\":{\"retry_after\":2}}")
+
+	response = httptest.NewRecorder()
+	context, _ = gin.CreateTestContext(response)
+
+	context.Error(fmt.Errorf("auth: %w", errutils.NewUnauthorized("users")))
+	handleErrorIfNeeded(context)
+
+	assert.Equal(t, response.Code, 401)
+	assert.Equal(t, response.Header().Get("WWW-Authenticate"), "Bearer realm=\"users\"")
+}
+
+func TestErrorXML(t *testing.T) {
+	response := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	context.Request.Header.Set("Accept", "application/xml")
+
+	context.Error(errutils.NewCustomError(400, "Custom Test"))
+	handleErrorIfNeeded(context)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Equal(t, response.Body.String(), "<map><error>Custom Test</error></map>")
+}
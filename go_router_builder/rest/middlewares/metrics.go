@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/metrics"
+)
+
+// UnmatchedRoute is the route label of requests that do not match a route,
+// raw paths would create a series for each one
+const UnmatchedRoute = "unmatched"
+
+// Metrics records the request metrics labeled with the route template
+func Metrics(recorder *metrics.HTTP) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		done := recorder.Start()
+
+		defer func() {
+			route := c.FullPath()
+			if route == "" {
+				route = UnmatchedRoute
+			}
+
+			done(metrics.Labels{
+				Method: c.Request.Method,
+				Route:  route,
+				Status: metrics.StatusClass(c.Writer.Status()),
+			}, time.Since(start))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/utils/ratelimit"
+)
+
+// KeyFunc identifies who is limited
+type KeyFunc func(c *gin.Context) string
+
+// RateLimitStoreKey is the gin context key of the store used by limits without their own
+const RateLimitStoreKey = "rate_limit_store"
+
+// RateLimitStore sets the store of the limits that do not set one, so each engine
+// can keep its own buckets
+func RateLimitStore(store ratelimit.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(RateLimitStoreKey, store)
+		c.Next()
+	}
+}
+
+// ClientIP limits each client ip. The ip is taken from X-Forwarded-For and X-Real-IP
+// only when the engine has ForwardedByClientIP, otherwise clients could avoid the
+// limit sending a different header on each request
+func ClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// APIKey limits each api key sent in header, requests without it are limited by client ip
+func APIKey(header string) KeyFunc {
+	return func(c *gin.Context) string {
+		if key := c.GetHeader(header); key != "" {
+			return "key:" + key
+		}
+		return ClientIP(c)
+	}
+}
+
+// AuthenticatedUser limits each user, anonymous requests are limited by client ip
+func AuthenticatedUser(c *gin.Context) string {
+	if id := c.GetString(UserIDKey); id != "" {
+		return "user:" + id
+	}
+	return ClientIP(c)
+}
+
+// RateLimitOptions configures a rate limit
+type RateLimitOptions struct {
+	// Store keeps the buckets, the one set by RateLimitStore when nil
+	Store ratelimit.Store
+	Limit ratelimit.Limit
+	// Limits replaces Limit when set, it is read on each request so the limit can change while serving
+	Limits func(c *gin.Context) ratelimit.Limit
+	// Key identifies who is limited, ClientIP when nil
+	Key KeyFunc
+	// Name separates the buckets of each limit, the route template when empty
+	Name string
+}
+
+// RateLimit answers 429 to the requests over the limit, every response has the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+func RateLimit(options RateLimitOptions) gin.HandlerFunc {
+	key := options.Key
+	if key == nil {
+		key = ClientIP
+	}
+
+	return func(c *gin.Context) {
+		name := options.Name
+		if name == "" {
+			name = c.FullPath()
+		}
+
+		store := options.Store
+		if store == nil {
+			store, _ = c.MustGet(RateLimitStoreKey).(ratelimit.Store)
+		}
+
+		limit := options.Limit
+		if options.Limits != nil {
+			limit = options.Limits(c)
+		}
+
+		result, err := store.Take(name+"|"+key(c), limit)
+		if err != nil {
+			// The store being down should not take the service down
+			log.Printf("Rate limit store failed %s : %s", requestPath(c), err.Error())
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+
+		if !result.Allowed {
+			c.Error(errors.NewRateLimited(result.RetryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/ratelimit"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func rateLimitEngine(options RateLimitOptions) *gin.Engine {
+	engine := gin.New()
+	engine.Use(ErrorHandler)
+	engine.GET("/users/:id", RateLimit(options), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return engine
+}
+
+func serveLimited(engine *gin.Engine, apiKey string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/users/123", nil)
+	request.Header.Set("X-Api-Key", apiKey)
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+func TestRateLimit(t *testing.T) {
+	engine := rateLimitEngine(RateLimitOptions{
+		Store: ratelimit.NewMemoryStore(),
+		Limit: ratelimit.Limit{Requests: 1, Per: time.Minute},
+		Key:   APIKey("X-Api-Key"),
+	})
+
+	response := serveLimited(engine, "first")
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("RateLimit-Limit"), "1")
+	assert.Equal(t, response.Header().Get("RateLimit-Remaining"), "0")
+	assert.Equal(t, response.Header().Get("RateLimit-Reset"), "60")
+
+	response = serveLimited(engine, "first")
+	assert.Equal(t, response.Code, 429)
+	assert.Equal(t, response.Header().Get("Retry-After"), "60")
+
+	assert.Equal(t, serveLimited(engine, "second").Code, 200)
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+func TestRateLimitStoreDown(t *testing.T) {
+	engine := rateLimitEngine(RateLimitOptions{
+		Store: failingStore{},
+		Limit: ratelimit.Limit{Requests: 1, Per: time.Minute},
+	})
+
+	assert.Equal(t, serveLimited(engine, "").Code, 200)
+	assert.Equal(t, serveLimited(engine, "").Code, 200)
+}
@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+	"github.com/nmarsollier/go_router_builder/utils/report"
+	"github.com/nmarsollier/go_router_builder/utils/uuid"
+)
+
+// RecoveredPanic is a panic captured in another goroutine, that keeps the original stack
+type RecoveredPanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Recovery a middleware that converts panics in the standard json error response
+func Recovery(c *gin.Context) {
+	recovery(c, RecoveryOptions{})
+}
+
+// RecoveryOptions configures the recovery middleware
+type RecoveryOptions struct {
+	// Reporter receives the panics, optional
+	Reporter report.Reporter
+}
+
+// NewRecovery a middleware that converts panics in the standard json error response,
+// using the given options
+func NewRecovery(options RecoveryOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recovery(c, options)
+	}
+}
+
+func recovery(c *gin.Context, options RecoveryOptions) {
+	defer func() {
+		if r := recover(); r != nil {
+			handlePanic(c, options, r, debug.Stack())
+		}
+	}()
+
+	c.Next()
+}
+
+func handlePanic(c *gin.Context, options RecoveryOptions, value interface{}, stack []byte) {
+	if recovered, ok := value.(*RecoveredPanic); ok {
+		value = recovered.Value
+		stack = recovered.Stack
+	}
+
+	incident := uuid.New()
+	log.Printf("Panic recovered %s, incident %s : %v\n%s", requestPath(c), incident, value, stack)
+
+	// Without the incident, that would keep the same panic from being deduplicated
+	if options.Reporter != nil {
+		sendReport(c, options.Reporter, http.StatusInternalServerError, fmt.Sprintf("panic: %v", value))
+	}
+
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+
+	body := gin.H{
+		"error":    i18n.Translate(i18n.Negotiate(acceptLanguage(c)), i18n.InternalServerError, nil),
+		"incident": incident,
+	}
+	if id := requestID(c); id != "" {
+		body["request_id"] = id
+	}
+	if gin.IsDebugging() {
+		body["stack"] = string(stack)
+	}
+
+	c.Abort()
+	gu.Render(c, http.StatusInternalServerError, body)
+}
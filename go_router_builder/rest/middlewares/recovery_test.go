@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/report"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func panicEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(Recovery, ErrorHandler)
+	engine.GET("/panic", func(c *gin.Context) {
+		c.MustGet("user")
+	})
+	engine.GET("/goroutine", func(c *gin.Context) {
+		panic(&RecoveredPanic{Value: "branch failed", Stack: []byte("branch stack")})
+	})
+	return engine
+}
+
+func servePanic(t *testing.T, path string) (int, map[string]string) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	panicEngine().ServeHTTP(response, request)
+
+	body := map[string]string{}
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return response.Code, body
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+
+	status, body := servePanic(t, "/panic")
+
+	assert.Equal(t, status, 500)
+	assert.Equal(t, body["error"], "Error interno del servidor")
+	assert.Equal(t, len(body["incident"]), 36)
+	assert.Equal(t, body["stack"], "")
+}
+
+func TestRecoveryDebugStack(t *testing.T) {
+	gin.SetMode(gin.DebugMode)
+
+	status, body := servePanic(t, "/goroutine")
+
+	assert.Equal(t, status, 500)
+	assert.Equal(t, body["stack"], "branch stack")
+}
+
+func TestRecoveryReporter(t *testing.T) {
+	var events []report.Event
+	reporter := report.ReporterFunc(func(event report.Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	engine := gin.New()
+	engine.Use(RequestID, NewRecovery(RecoveryOptions{Reporter: reporter}))
+	engine.GET("/goroutine", func(c *gin.Context) {
+		panic(&RecoveredPanic{Value: "branch failed", Stack: []byte("branch stack")})
+	})
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/goroutine", nil)
+	request.Header.Set("X-Request-ID", "req-1")
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 500)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Status, 500)
+	assert.Equal(t, events[0].Message, "panic: branch failed")
+	assert.Equal(t, events[0].Path, "/goroutine")
+	assert.Equal(t, events[0].RequestID, "req-1")
+}
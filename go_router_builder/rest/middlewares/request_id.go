@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/requestid"
+)
+
+// RequestIDKey is the gin context key of the request id
+const RequestIDKey = "request_id"
+
+// RequestID takes the X-Request-ID of the request, or generates one, and stores it
+// in the request context and in the response headers
+func RequestID(c *gin.Context) {
+	id := requestid.Resolve(c.GetHeader(requestid.Header))
+
+	c.Set(RequestIDKey, id)
+	c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+	c.Header(requestid.Header, id)
+
+	c.Next()
+}
+
+// requestID is the request id of the context, empty when RequestID did not run
+func requestID(c *gin.Context) string {
+	if c.Request == nil {
+		return ""
+	}
+	return requestid.FromContext(c.Request.Context())
+}
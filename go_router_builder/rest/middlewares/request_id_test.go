@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/requestid"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func requestIDEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(RequestID, ErrorHandler)
+	engine.GET("/id", func(c *gin.Context) {
+		c.String(http.StatusOK, requestid.FromContext(c.Request.Context()))
+	})
+	engine.GET("/error", func(c *gin.Context) {
+		c.Error(errors.New("failed"))
+	})
+	return engine
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/id", nil)
+	request.Header.Set("X-Request-ID", "abc-123")
+	requestIDEngine().ServeHTTP(response, request)
+
+	assert.Equal(t, response.Body.String(), "abc-123")
+	assert.Equal(t, response.Header().Get("X-Request-ID"), "abc-123")
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/id", nil)
+	request.Header.Set("X-Request-ID", "forged\nline")
+	requestIDEngine().ServeHTTP(response, request)
+
+	assert.Equal(t, len(response.Body.String()), 36)
+	assert.Equal(t, response.Header().Get("X-Request-ID"), response.Body.String())
+}
+
+func TestRequestIDInErrors(t *testing.T) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/error", nil)
+	request.Header.Set("X-Request-ID", "abc-123")
+	requestIDEngine().ServeHTTP(response, request)
+
+	body := map[string]string{}
+	json.Unmarshal(response.Body.Bytes(), &body)
+	assert.Equal(t, body["request_id"], "abc-123")
+}
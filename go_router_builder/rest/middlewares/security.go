@@ -0,0 +1,132 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultSecurityHeaders are sent in every response, routes can override them with SecurityHeaders
+var DefaultSecurityHeaders = map[string]string{
+	"X-Content-Type-Options":    "nosniff",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	"Referrer-Policy":           "no-referrer",
+	"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+}
+
+// SecurityOptions configures CORS and the security headers
+type SecurityOptions struct {
+	// AllowedOrigins are the origins allowed to call, "*" allows any
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+	// Headers are the security headers, DefaultSecurityHeaders when nil
+	Headers map[string]string
+}
+
+// NewSecurity answers CORS preflight requests, adds the CORS headers to allowed origins
+// and the security headers to every response
+func NewSecurity(options SecurityOptions) gin.HandlerFunc {
+	headers := options.Headers
+	if headers == nil {
+		headers = DefaultSecurityHeaders
+	}
+
+	anyOrigin := false
+	origins := map[string]bool{}
+	for _, origin := range options.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+		origins[strings.ToLower(origin)] = true
+	}
+
+	methods := toSet(options.AllowedMethods, strings.ToUpper)
+	allowedHeaders := toSet(options.AllowedHeaders, http.CanonicalHeaderKey)
+
+	return func(c *gin.Context) {
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		allowed := anyOrigin || origins[strings.ToLower(origin)]
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+			if !allowed ||
+				!methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] ||
+				!allHeadersAllowed(c.GetHeader("Access-Control-Request-Headers"), allowedHeaders) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			allowOrigin(c, origin, anyOrigin, options.AllowCredentials)
+			c.Header("Access-Control-Allow-Methods", strings.Join(options.AllowedMethods, ", "))
+			if len(options.AllowedHeaders) > 0 {
+				c.Header("Access-Control-Allow-Headers", strings.Join(options.AllowedHeaders, ", "))
+			}
+			if options.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if allowed {
+			allowOrigin(c, origin, anyOrigin, options.AllowCredentials)
+			if len(options.ExposedHeaders) > 0 {
+				c.Header("Access-Control-Expose-Headers", strings.Join(options.ExposedHeaders, ", "))
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// SecurityHeaders overrides the security headers of a route, an empty value removes the header
+func SecurityHeaders(headers map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+		c.Next()
+	}
+}
+
+// Credentials can not be sent to the "*" origin, so the origin is echoed
+func allowOrigin(c *gin.Context, origin string, anyOrigin, credentials bool) {
+	if anyOrigin && !credentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+	if credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func allHeadersAllowed(requested string, allowed map[string]bool) bool {
+	for _, name := range strings.Split(requested, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !allowed[http.CanonicalHeaderKey(name)] {
+			return false
+		}
+	}
+	return true
+}
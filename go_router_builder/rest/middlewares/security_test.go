@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func securityEngine(options SecurityOptions) *gin.Engine {
+	engine := gin.New()
+	engine.Use(NewSecurity(options))
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/docs", SecurityHeaders(map[string]string{
+		"Content-Security-Policy": "default-src 'self'",
+		"Referrer-Policy":         "",
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, "docs")
+	})
+	return engine
+}
+
+func serveSecurity(engine *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+var corsOptions = SecurityOptions{
+	AllowedOrigins:   []string{"https://app.example.com"},
+	AllowedMethods:   []string{"GET", "POST"},
+	AllowedHeaders:   []string{"Authorization", "X-Request-ID"},
+	ExposedHeaders:   []string{"X-Request-ID"},
+	AllowCredentials: true,
+	MaxAge:           10 * time.Minute,
+}
+
+func TestPreflight(t *testing.T) {
+	engine := securityEngine(corsOptions)
+
+	response := serveSecurity(engine, "OPTIONS", "/users/123", map[string]string{
+		"Origin":                         "https://app.example.com",
+		"Access-Control-Request-Method":  "GET",
+		"Access-Control-Request-Headers": "authorization",
+	})
+	assert.Equal(t, response.Code, 204)
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "https://app.example.com")
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Credentials"), "true")
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Methods"), "GET, POST")
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Headers"), "Authorization, X-Request-ID")
+	assert.Equal(t, response.Header().Get("Access-Control-Max-Age"), "600")
+
+	response = serveSecurity(engine, "OPTIONS", "/users/123", map[string]string{
+		"Origin":                        "https://app.example.com",
+		"Access-Control-Request-Method": "DELETE",
+	})
+	assert.Equal(t, response.Code, 403)
+
+	response = serveSecurity(engine, "OPTIONS", "/users/123", map[string]string{
+		"Origin":                        "https://evil.example.com",
+		"Access-Control-Request-Method": "GET",
+	})
+	assert.Equal(t, response.Code, 403)
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "")
+}
+
+func TestCORSRequest(t *testing.T) {
+	engine := securityEngine(corsOptions)
+
+	response := serveSecurity(engine, "GET", "/users/123", map[string]string{"Origin": "https://app.example.com"})
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "https://app.example.com")
+	assert.Equal(t, response.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+	assert.Equal(t, response.Header().Get("Vary"), "Origin")
+
+	response = serveSecurity(engine, "GET", "/users/123", map[string]string{"Origin": "https://evil.example.com"})
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "")
+
+	anyOrigin := securityEngine(SecurityOptions{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}})
+	response = serveSecurity(anyOrigin, "GET", "/users/123", map[string]string{"Origin": "https://other.example.com"})
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "*")
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	engine := securityEngine(SecurityOptions{})
+
+	response := serveSecurity(engine, "GET", "/users/123", nil)
+	assert.Equal(t, response.Header().Get("X-Content-Type-Options"), "nosniff")
+	assert.Equal(t, response.Header().Get("Strict-Transport-Security"), "max-age=31536000; includeSubDomains")
+	assert.Equal(t, response.Header().Get("Referrer-Policy"), "no-referrer")
+	assert.Equal(t, response.Header().Get("Content-Security-Policy"), "default-src 'none'; frame-ancestors 'none'")
+
+	response = serveSecurity(engine, "GET", "/docs", nil)
+	assert.Equal(t, response.Header().Get("Content-Security-Policy"), "default-src 'self'")
+	assert.Equal(t, response.Header().Get("Referrer-Policy"), "")
+	assert.Equal(t, response.Header().Get("X-Content-Type-Options"), "nosniff")
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutOptions configures the request deadlines
+type TimeoutOptions struct {
+	// Default is the timeout of routes not in Routes, zero means no deadline
+	Default time.Duration
+	// Routes are the timeouts by "METHOD /route/:template"
+	Routes map[string]time.Duration
+}
+
+// NewTimeout puts a deadline in the request context, services that get the context
+// stop when it expires and the error handler answers 504
+func NewTimeout(options TimeoutOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := options.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = options.Default
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
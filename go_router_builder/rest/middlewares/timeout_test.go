@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestTimeout(t *testing.T) {
+	engine := gin.New()
+	engine.Use(ErrorHandler, NewTimeout(TimeoutOptions{
+		Default: 10 * time.Millisecond,
+		Routes:  map[string]time.Duration{"GET /slow/:id": time.Second},
+	}))
+	wait := func(c *gin.Context) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			c.String(http.StatusOK, "done")
+		case <-c.Request.Context().Done():
+			c.Error(c.Request.Context().Err())
+		}
+	}
+	engine.GET("/fast/:id", wait)
+	engine.GET("/slow/:id", wait)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/fast/1", nil)
+	engine.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, 504)
+
+	response = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", "/slow/1", nil)
+	engine.ServeHTTP(response, request)
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "done")
+}
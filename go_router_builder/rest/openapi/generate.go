@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Route is the route information used to generate the document
+type Route struct {
+	Method     string
+	Path       string
+	Summary    string
+	Tags       []string
+	Params     []Parameter
+	Response   interface{}
+	Errors     []int
+	Deprecated bool
+}
+
+// Generate creates the document for the routes
+func Generate(info Info, routes []Route) *Document {
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, r := range routes {
+		path, pathParams := convertPath(r.Path)
+
+		operation := &Operation{
+			OperationID: operationID(r.Method, r.Path),
+			Summary:     r.Summary,
+			Tags:        r.Tags,
+			Parameters:  parameters(pathParams, r.Params),
+			Responses:   responses(r.Response, r.Errors),
+			Deprecated:  r.Deprecated,
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = PathItem{}
+		}
+		document.Paths[path][strings.ToLower(r.Method)] = operation
+	}
+
+	return document
+}
+
+// SchemaOf creates the schema of the value type
+func SchemaOf(value interface{}) *Schema {
+	if value == nil {
+		return nil
+	}
+	return schemaOf(reflect.TypeOf(value))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+			schema.Properties[name] = schemaOf(field.Type)
+		}
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return tag
+	}
+	return field.Name
+}
+
+// gin paths like /users/:id are /users/{id} in openapi
+func convertPath(path string) (string, []string) {
+	var params []string
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func operationID(method, path string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "*", "")
+	return strings.ToLower(method) + strings.TrimRight(replacer.Replace(path), "_")
+}
+
+func parameters(pathParams []string, declared []Parameter) []Parameter {
+	result := append([]Parameter{}, declared...)
+
+	for _, name := range pathParams {
+		found := false
+		for i, param := range result {
+			if param.In == "path" && param.Name == name {
+				result[i].Required = true
+				found = true
+			}
+		}
+
+		if !found {
+			result = append(result, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	return result
+}
+
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"error": {Type: "string"},
+	},
+}
+
+func responses(body interface{}, errors []int) map[string]Response {
+	result := map[string]Response{
+		"200": {
+			Description: http.StatusText(http.StatusOK),
+			Content:     content(SchemaOf(body)),
+		},
+	}
+
+	for _, status := range errors {
+		result[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     content(errorSchema),
+		}
+	}
+
+	return result
+}
+
+func content(schema *Schema) map[string]MediaType {
+	if schema == nil {
+		return nil
+	}
+	return map[string]MediaType{
+		"application/json": {Schema: schema},
+	}
+}
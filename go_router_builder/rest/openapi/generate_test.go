@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+type answer struct {
+	Name    string    `json:"name"`
+	Tags    []string  `json:"tags"`
+	Count   int       `json:"count,omitempty"`
+	Created time.Time `json:"created"`
+	Hidden  string    `json:"-"`
+	private string
+}
+
+func TestGenerate(t *testing.T) {
+	document := Generate(Info{Title: "test", Version: "1"}, []Route{
+		{
+			Method:   "GET",
+			Path:     "/users/:id",
+			Summary:  "User",
+			Params:   []Parameter{{Name: "verbose", In: "query", Schema: &Schema{Type: "boolean"}}},
+			Response: answer{},
+			Errors:   []int{404},
+		},
+	})
+
+	assert.Equal(t, document.OpenAPI, "3.0.3")
+
+	operation := document.Paths["/users/{id}"]["get"]
+	assert.Equal(t, operation.OperationID, "get_users_id")
+	assert.Equal(t, operation.Summary, "User")
+	assert.Equal(t, len(operation.Parameters), 2)
+	assert.Equal(t, operation.Parameters[1].Name, "id")
+	assert.Equal(t, operation.Parameters[1].In, "path")
+	assert.Equal(t, operation.Parameters[1].Required, true)
+
+	assert.Equal(t, operation.Responses["404"].Description, "Not Found")
+	assert.Equal(t, operation.Responses["404"].Content["application/json"].Schema, errorSchema)
+}
+
+func TestDeclaredPathParamIsRequired(t *testing.T) {
+	document := Generate(Info{}, []Route{
+		{
+			Method: "GET",
+			Path:   "/users/:id",
+			Params: []Parameter{{Name: "id", In: "path", Schema: &Schema{Type: "string"}}},
+		},
+	})
+
+	parameters := document.Paths["/users/{id}"]["get"].Parameters
+	assert.Equal(t, len(parameters), 1)
+	assert.Equal(t, parameters[0].Required, true)
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf(&answer{})
+
+	assert.Equal(t, schema.Type, "object")
+	assert.Equal(t, len(schema.Properties), 4)
+	assert.Equal(t, schema.Properties["name"].Type, "string")
+	assert.Equal(t, schema.Properties["tags"].Type, "array")
+	assert.Equal(t, schema.Properties["tags"].Items.Type, "string")
+	assert.Equal(t, schema.Properties["count"].Type, "integer")
+	assert.Equal(t, schema.Properties["created"].Format, "date-time")
+
+	assert.Equal(t, SchemaOf(nil) == nil, true)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import "time"
+
+// API son las versiones publicadas de los servicios de usuarios.
+// v1 sigue siendo la version por defecto, pero esta deprecada a favor de v2
+var API = Versioned("v1",
+	Version{
+		Name:        "v1",
+		Routes:      append(append(Routes{}, Users...), ParallelUsers...),
+		Deprecation: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:      time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+		Link:        "/openapi.json",
+	},
+	Version{
+		Name:   "v2",
+		Routes: UsersV2,
+	},
+)
@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo describes a route as the engine serves it
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Version is the Accept-Version that selects this chain in unprefixed paths of Versioned
+	Version    string   `json:"version,omitempty"`
+	Middleware []string `json:"middleware"`
+	Handlers   []string `json:"handlers"`
+	Summary    string   `json:"summary,omitempty"`
+}
+
+// Describe lists the routes of the engine NewEngine builds with opts and modules, with the
+// names of the middleware each one runs and of each handler in the chain.
+// Unprefixed paths of Versioned have a row per version
+func Describe(opts Options, modules ...Routes) ([]RouteInfo, error) {
+	var registered []RouteInfo
+	engine, err := newEngine(opts, &registered, modules...)
+	if err != nil {
+		return nil, err
+	}
+
+	order := map[string]int{}
+	byRoute := map[string][]RouteInfo{}
+	for i, info := range registered {
+		key := info.Method + " " + info.Path
+		if _, ok := order[key]; !ok {
+			order[key] = i
+		}
+		byRoute[key] = append(byRoute[key], info)
+	}
+
+	// The engine routes are what is served, in the order they were registered
+	served := engine.Routes()
+	sort.SliceStable(served, func(i, j int) bool {
+		first, ok := order[served[i].Method+" "+served[i].Path]
+		if !ok {
+			return false
+		}
+		second, ok := order[served[j].Method+" "+served[j].Path]
+		return !ok || first < second
+	})
+
+	var result []RouteInfo
+	for _, route := range served {
+		infos, ok := byRoute[route.Method+" "+route.Path]
+		if !ok {
+			infos = []RouteInfo{{
+				Method:   route.Method,
+				Path:     route.Path,
+				Handlers: []string{HandlerName(route.HandlerFunc)},
+			}}
+		}
+		result = append(result, infos...)
+	}
+	return result, nil
+}
+
+// described are the routes listing rows of r, served after the middleware
+func (r Route) described(middleware []string) []RouteInfo {
+	if r.versions == nil {
+		return []RouteInfo{{
+			Method:     r.Method,
+			Path:       r.Path,
+			Middleware: middleware,
+			Handlers:   stepNames(r.steps()),
+			Summary:    r.Meta.Summary,
+		}}
+	}
+
+	result := make([]RouteInfo, len(r.versions.names))
+	for i, name := range r.versions.names {
+		version := r.versions.routes[name]
+		result[i] = RouteInfo{
+			Method:     r.Method,
+			Path:       r.Path,
+			Version:    name,
+			Middleware: middleware,
+			Handlers:   stepNames(version.steps()),
+			Summary:    version.Meta.Summary,
+		}
+	}
+	return result
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// HandlerName is the name to show for a handler, the function name without
+// the package path, routes package functions are shown without package.
+// Closures show the name of the function that returns them
+func HandlerName(handler gin.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = closureSuffix.ReplaceAllString(name, "")
+	return strings.TrimPrefix(name, "routes.")
+}
+
+func handlerNames(handlers []gin.HandlerFunc) []string {
+	result := make([]string, len(handlers))
+	for i, handler := range handlers {
+		result[i] = HandlerName(handler)
+	}
+	return result
+}
+
+func stepNames(steps []Step) []string {
+	result := make([]string, len(steps))
+	for i, step := range steps {
+		result[i] = step.Name
+	}
+	return result
+}
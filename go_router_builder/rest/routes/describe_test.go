@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestDescribe(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+
+	infos, err := Describe(Options{Config: config.Static(cfg), Metrics: true}, Users, ParallelUsers)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, len(infos), 3)
+	assert.Equal(t, infos[0].Path, "/metrics")
+	assert.Equal(t, infos[0].Handlers, []string{"writeMetrics"})
+	assert.Equal(t, infos[0].Middleware, []string{
+		"middlewares.RequestID",
+		"middlewares.NewAccessLog",
+		"middlewares.Metrics",
+	})
+
+	assert.Equal(t, infos[1].Path, "/users/:id")
+	assert.Equal(t, infos[1].Handlers, []string{"validate(id)", "middlewares.RateLimit", "fetchUser", "fetchProfile", "build"})
+	assert.Equal(t, infos[2].Handlers, []string{
+		"validate(id)",
+		"middlewares.RateLimit",
+		"inParallel(fetchUserInParallel, fetchProfileInParallel)",
+		"build",
+	})
+	assert.Equal(t, infos[2].Middleware, []string{
+		"middlewares.RequestID",
+		"middlewares.NewAccessLog",
+		"middlewares.Metrics",
+		"middlewares.NewRecovery",
+		"middlewares.NewSecurity",
+		"middlewares.Acceptable",
+		"middlewares.NewCompression",
+		"middlewares.NewErrorHandler",
+		"config.Middleware",
+		"middlewares.RateLimitStore",
+		"middlewares.NewTimeout",
+		"middlewares.Authenticate",
+	})
+}
+
+func TestDescribeVersions(t *testing.T) {
+	infos, _ := Describe(Options{}, API)
+
+	var versions, first []string
+	for _, info := range infos {
+		if info.Path == "/users/:id" {
+			versions = append(versions, info.Version)
+			first = append(first, info.Handlers[0])
+		}
+	}
+	assert.Equal(t, versions, []string{"v1", "v2"})
+	assert.Equal(t, first, []string{"securityHeaders", "validate(id)"})
+}
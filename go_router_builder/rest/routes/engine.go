@@ -0,0 +1,305 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+	"github.com/nmarsollier/go_router_builder/rest/validation"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"github.com/nmarsollier/go_router_builder/utils/jwt"
+	"github.com/nmarsollier/go_router_builder/utils/metrics"
+	"github.com/nmarsollier/go_router_builder/utils/ratelimit"
+	"github.com/nmarsollier/go_router_builder/utils/report"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
+)
+
+// Meta describes a route
+type Meta struct {
+	Summary string
+	Tags    []string
+	// Params documents the path, query and header parameters, path parameters
+	// not listed here are documented as required strings
+	Params []openapi.Parameter
+	// Response is a value of the type sent on success
+	Response interface{}
+	// Errors are the error statuses the route can answer
+	Errors []int
+	// Deprecated routes will be removed
+	Deprecated bool
+}
+
+// Route declares a handler chain for a method and path
+type Route struct {
+	Method   string
+	Path     string
+	Handlers []Step
+	// Validate are the parameter rules checked before the handlers
+	Validate validation.Rules
+	// Roles the authenticated user needs, the route is public when empty
+	Roles []string
+	// Headers override the default security headers, an empty value removes the header
+	Headers map[string]string
+	// Timeout is the request deadline, the config request_timeout when zero
+	Timeout time.Duration
+	Meta    Meta
+
+	// versions serve an unprefixed path of Versioned
+	versions *pathVersions
+}
+
+// Step is a handler of the route chain, with the name the routes listing shows
+type Step struct {
+	Name    string
+	Handler gin.HandlerFunc
+}
+
+// Handle is the step of a handler, named after its function
+func Handle(handler gin.HandlerFunc) Step {
+	return Step{Name: HandlerName(handler), Handler: handler}
+}
+
+// Chain are the steps of handlers, named after their functions
+func Chain(handlers ...gin.HandlerFunc) []Step {
+	result := make([]Step, len(handlers))
+	for i, handler := range handlers {
+		result[i] = Handle(handler)
+	}
+	return result
+}
+
+// steps are the handler chain of the route, with the validation of its rules first
+func (r Route) steps() []Step {
+	var result []Step
+
+	if len(r.Headers) > 0 {
+		result = append(result, Step{"securityHeaders", middlewares.SecurityHeaders(r.Headers)})
+	}
+
+	if len(r.Roles) > 0 {
+		result = append(result, Step{
+			"requireRoles(" + strings.Join(r.Roles, ", ") + ")",
+			middlewares.RequireRoles(r.Roles...),
+		})
+	}
+
+	if len(r.Validate) > 0 {
+		params := make([]string, len(r.Validate))
+		for i, rule := range r.Validate {
+			params[i] = rule.Name()
+		}
+		result = append(result, Step{"validate(" + strings.Join(params, ", ") + ")", r.Validate.Handler()})
+	}
+
+	return append(result, r.Handlers...)
+}
+
+// chain is the handler chain of the route
+func (r Route) chain() []gin.HandlerFunc {
+	steps := r.steps()
+	result := make([]gin.HandlerFunc, len(steps))
+	for i, step := range steps {
+		result[i] = step.Handler
+	}
+	return result
+}
+
+// Routes are the routes a feature exposes
+type Routes []Route
+
+// Methods and headers browsers can use in cross origin requests
+var (
+	corsMethods        = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	corsHeaders        = []string{"Accept", "Accept-Language", "Authorization", "Content-Type", "X-Api-Key", "X-Request-ID"}
+	corsExposedHeaders = []string{"X-Request-ID", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"}
+)
+
+// Options configures a new engine
+type Options struct {
+	Config *config.Store
+	// Shutdown receives the hooks to release the engine resources, optional
+	Shutdown *shutdown.Hooks
+	// Metrics records the request metrics and serves them in /metrics
+	Metrics bool
+	// RateLimits keeps the rate limit buckets, a new in memory store when nil
+	RateLimits ratelimit.Store
+}
+
+// NewEngine creates a new engine with the standard middlewares and the routes of the modules
+func NewEngine(opts Options, modules ...Routes) (*gin.Engine, error) {
+	return newEngine(opts, nil, modules...)
+}
+
+// newEngine creates the engine, adding the routes it registers to registered when not nil
+func newEngine(opts Options, registered *[]RouteInfo, modules ...Routes) (*gin.Engine, error) {
+	if opts.Config == nil {
+		opts.Config = config.Static(config.Defaults())
+	}
+	if opts.Shutdown == nil {
+		opts.Shutdown = &shutdown.Hooks{}
+	}
+	if opts.RateLimits == nil {
+		opts.RateLimits = ratelimit.NewMemoryStore()
+	}
+	cfg := opts.Config.Current()
+
+	reporter, err := newReporter(cfg.Report, opts.Shutdown)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := newKeys(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := gin.New()
+	// Forwarded headers can be forged, unless a proxy we trust sets them
+	engine.ForwardedByClientIP = cfg.TrustForwardedFor
+	engine.Use(middlewares.RequestID)
+	engine.Use(middlewares.NewAccessLog(middlewares.AccessLogOptions{
+		Sampling: func() middlewares.AccessLogSampling {
+			accessLog := opts.Config.Current().AccessLog
+			return middlewares.AccessLogSampling{
+				Sample: accessLog.Sample,
+				Slow:   accessLog.Slow.Duration,
+				Level:  accessLog.Level,
+			}
+		},
+		RedactQuery:   cfg.AccessLog.RedactQuery,
+		Headers:       cfg.AccessLog.Headers,
+		RedactHeaders: cfg.AccessLog.RedactHeaders,
+	}))
+	if opts.Metrics {
+		// Before Recovery, so panics are recorded with the status Recovery answers
+		recorder := metrics.NewHTTP()
+		engine.Use(middlewares.Metrics(recorder))
+		engine.GET("/metrics", writeMetrics(recorder))
+		if registered != nil {
+			*registered = append(*registered, RouteInfo{
+				Method:     http.MethodGet,
+				Path:       "/metrics",
+				Middleware: handlerNames(engine.Handlers),
+				Handlers:   []string{"writeMetrics"},
+				Summary:    "Prometheus metrics",
+			})
+		}
+	}
+	engine.Use(middlewares.NewRecovery(middlewares.RecoveryOptions{
+		Reporter: reporter,
+	}))
+	engine.Use(middlewares.NewSecurity(middlewares.SecurityOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   corsMethods,
+		AllowedHeaders:   corsHeaders,
+		ExposedHeaders:   corsExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge.Duration,
+	}))
+	engine.Use(middlewares.Acceptable)
+	engine.Use(middlewares.NewCompression(middlewares.CompressionOptions{
+		MinSize: 1024,
+	}))
+	engine.Use(middlewares.NewErrorHandler(middlewares.ErrorOptions{
+		Reporter:   reporter,
+		Production: cfg.Production(),
+	}))
+	engine.Use(config.Middleware(opts.Config))
+	engine.Use(middlewares.RateLimitStore(opts.RateLimits))
+	engine.Use(middlewares.NewTimeout(middlewares.TimeoutOptions{
+		Default: cfg.RequestTimeout.Duration,
+		Routes:  timeouts(modules),
+	}))
+	engine.Use(middlewares.Authenticate(middlewares.AuthOptions{
+		Keys:  keys,
+		Realm: cfg.Auth.Realm,
+	}))
+
+	for _, routes := range modules {
+		for _, r := range routes {
+			r = r.negotiated(cfg.RequestTimeout.Duration)
+			engine.Handle(r.Method, r.Path, r.chain()...)
+			if registered != nil {
+				*registered = append(*registered, r.described(handlerNames(engine.Handlers))...)
+			}
+		}
+	}
+
+	return engine, nil
+}
+
+func writeMetrics(recorder *metrics.HTTP) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := recorder.WriteText(c.Writer); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+func timeouts(modules []Routes) map[string]time.Duration {
+	result := map[string]time.Duration{}
+	for _, routes := range modules {
+		for _, r := range routes {
+			if r.versions != nil {
+				// No deadline, the negotiated version sets its own
+				result[r.Method+" "+r.Path] = 0
+			} else if r.Timeout > 0 {
+				result[r.Method+" "+r.Path] = r.Timeout
+			}
+		}
+	}
+	return result
+}
+
+func newReporter(cfg config.Report, hooks *shutdown.Hooks) (report.Reporter, error) {
+	var reporter report.Reporter
+	switch cfg.Output {
+	case "none":
+		return nil, nil
+	case "file":
+		file, err := report.NewFileReporter(cfg.File, 10*1024*1024, 5)
+		if err != nil {
+			return nil, err
+		}
+		hooks.Add("file reporter", func(ctx context.Context) error {
+			return file.Close()
+		})
+		reporter = file
+	case "sentry":
+		sentry, err := report.NewSentryReporter(cfg.SentryDSN)
+		if err != nil {
+			return nil, err
+		}
+		hooks.Add("sentry reporter", func(ctx context.Context) error {
+			return sentry.Close(ctx)
+		})
+		reporter = sentry
+	default:
+		reporter = report.NewStdoutReporter()
+	}
+
+	// Sampled before deduplicating, so a dropped event does not hide the next ones
+	return report.Sample(report.Deduplicate(reporter, time.Minute), cfg.SampleRates), nil
+}
+
+// The keys that verify bearer tokens, nil when none is configured
+func newKeys(cfg config.Auth) (*jwt.Keys, error) {
+	if cfg.Secret == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+
+	keys := &jwt.Keys{Secret: []byte(cfg.Secret)}
+	if cfg.JWKSFile != "" {
+		public, err := jwt.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys.Public = public
+	}
+	return keys, nil
+}
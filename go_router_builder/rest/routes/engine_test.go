@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"github.com/nmarsollier/go_router_builder/utils/ratelimit"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func ping(answer string) Routes {
+	return Routes{
+		{
+			Method: http.MethodGet,
+			Path:   "/ping",
+			Handlers: Chain(func(c *gin.Context) {
+				c.String(http.StatusOK, answer)
+			}),
+		},
+	}
+}
+
+func get(engine *gin.Engine, path string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+func TestIndependentEngines(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	opts := Options{Config: config.Static(cfg)}
+
+	first, _ := NewEngine(opts, ping("first"))
+	second, _ := NewEngine(opts, ping("second"), Users)
+
+	assert.Equal(t, get(first, "/ping").Body.String(), "first")
+	assert.Equal(t, get(second, "/ping").Body.String(), "second")
+
+	assert.Equal(t, get(first, "/users/").Code, 404)
+	assert.Equal(t, len(second.Routes()), 2)
+}
+
+func TestMetrics(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+
+	withMetrics, _ := NewEngine(Options{Config: config.Static(cfg), Metrics: true}, ping("pong"))
+	withoutMetrics, _ := NewEngine(Options{Config: config.Static(cfg)}, ping("pong"))
+
+	get(withMetrics, "/ping")
+	get(withMetrics, "/unknown")
+
+	response := get(withMetrics, "/metrics")
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, strings.Contains(response.Body.String(),
+		`http_requests_total{method="GET",route="/ping",status="2xx"} 1`), true)
+	assert.Equal(t, strings.Contains(response.Body.String(),
+		`http_requests_total{method="GET",route="unmatched",status="4xx"} 1`), true)
+
+	assert.Equal(t, get(withoutMetrics, "/metrics").Code, 404)
+}
+
+func TestRouteRoles(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	cfg.Auth.Secret = "secret"
+
+	admin := ping("pong")
+	admin[0].Roles = []string{"ADMIN"}
+	engine, _ := NewEngine(Options{Config: config.Static(cfg)}, admin)
+
+	assert.Equal(t, get(engine, "/ping").Code, 401)
+	infos, _ := Describe(Options{Config: config.Static(cfg)}, admin)
+	assert.Equal(t, infos[0].Handlers[0], "requireRoles(ADMIN)")
+	assert.Equal(t, OpenAPI(admin).Paths["/ping"]["get"].Responses["403"].Description, "Forbidden")
+}
+
+func TestPreflightWithoutOptionsRoute(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	cfg.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	engine, _ := NewEngine(Options{Config: config.Static(cfg)}, ping("pong"))
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("OPTIONS", "/ping", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	request.Header.Set("Access-Control-Request-Method", "GET")
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 204)
+	assert.Equal(t, response.Header().Get("Access-Control-Allow-Origin"), "https://app.example.com")
+}
+
+func TestIndependentRateLimits(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	opts := Options{Config: config.Static(cfg)}
+
+	limited := Routes{{
+		Method: "GET",
+		Path:   "/limited",
+		Handlers: Chain(
+			middlewares.RateLimit(middlewares.RateLimitOptions{
+				Limit: ratelimit.Limit{Requests: 1, Per: time.Minute},
+			}),
+			func(c *gin.Context) { c.String(200, "ok") },
+		),
+	}}
+
+	first, _ := NewEngine(opts, limited)
+	second, _ := NewEngine(opts, limited)
+
+	assert.Equal(t, get(first, "/limited").Code, 200)
+	assert.Equal(t, get(first, "/limited").Code, 429)
+	assert.Equal(t, get(second, "/limited").Code, 200)
+}
@@ -1,49 +1,120 @@
 package routes
 
 import (
+	"net/http"
+	"runtime/debug"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nmarsollier/go_router_builder/model/profile"
 	"github.com/nmarsollier/go_router_builder/model/user"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/rest/validation"
+	"github.com/nmarsollier/go_router_builder/utils/config"
 )
 
-// Servicio REST que nos retorna información de un dialogo a mostrar en pantalla
+// ParallelUsers servicio REST que nos retorna información de un dialogo a mostrar en pantalla
 // Vamos a usar el contexto como un Builder Pattern
-func init() {
-	router().GET(
-		"/parallel/users/:id",
-		validateUserName,
-		inParallel(
-			fetchUserInParallel,
-			fetchProfileInParallel,
-		),
-		build,
-	)
+var ParallelUsers = Routes{
+	{
+		Method: http.MethodGet,
+		Path:   "/parallel/users/:id",
+		Handlers: []Step{
+			Handle(middlewares.RateLimit(usersLimit)),
+			inParallel(
+				fetchUserInParallel,
+				fetchProfileInParallel,
+			),
+			Handle(build),
+		},
+		Validate: validation.Rules{
+			validation.Param("id").MinLen(1).Describe("User id"),
+		},
+		Meta: Meta{
+			Summary:  "User and profile information, fetched in parallel",
+			Tags:     []string{"users"},
+			Response: userAnswer{},
+			Errors:   []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+		},
+	},
 }
 
 func fetchUserInParallel(c *gin.Context) {
-	c.Set("user", user.FetchUser())
+	data, err := user.FetchUser(c.Request.Context(), config.Get(c).UserURL, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Set("user", data)
 }
 
 func fetchProfileInParallel(c *gin.Context) {
-	c.Set("profile", profile.FetchProfile())
+	data, err := profile.FetchProfile(c.Request.Context(), config.Get(c).ProfileURL, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Set("profile", data)
+}
+
+// Cada handler corre sobre una copia del contexto, luego juntamos
+// los valores y errores en el contexto original, para no tener
+// escrituras concurrentes sobre el mismo contexto.
+// El paso se nombra con los handlers que ejecuta
+func inParallel(handlers ...gin.HandlerFunc) Step {
+	return Step{
+		Name:    "inParallel(" + strings.Join(handlerNames(handlers), ", ") + ")",
+		Handler: parallel(handlers),
+	}
 }
 
-func inParallel(handlers ...gin.HandlerFunc) gin.HandlerFunc {
+func parallel(handlers []gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var waitGroup sync.WaitGroup
 		waitGroup.Add(len(handlers))
 
-		for _, handler := range handlers {
-			go func(handlerFunc gin.HandlerFunc) {
+		branches := make([]*gin.Context, len(handlers))
+		panics := make([]*middlewares.RecoveredPanic, len(handlers))
+		for i, handler := range handlers {
+			branches[i] = c.Copy()
+			go func(i int, handlerFunc gin.HandlerFunc) {
 				defer waitGroup.Done()
-				handlerFunc(c)
-			}(handler)
+				defer func() {
+					if r := recover(); r != nil {
+						panics[i] = &middlewares.RecoveredPanic{Value: r, Stack: debug.Stack()}
+					}
+				}()
+				handlerFunc(branches[i])
+			}(i, handler)
 		}
 
 		waitGroup.Wait()
 
+		// Un panic en una goroutine no lo puede recuperar el middleware,
+		// lo relanzamos en la goroutine del request
+		for _, recovered := range panics {
+			if recovered != nil {
+				panic(recovered)
+			}
+		}
+
+		for _, branch := range branches {
+			for key, value := range branch.Keys {
+				c.Set(key, value)
+			}
+			for _, err := range branch.Errors {
+				c.Error(err)
+			}
+		}
+
+		if len(c.Errors) > 0 {
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/utils/test"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestInParallel(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/parallel/users/123", nil)
+
+	inParallel(
+		func(c *gin.Context) {
+			c.Set("user", "nmarsollier")
+		},
+		func(c *gin.Context) {
+			c.Error(errors.New("profile error"))
+		},
+		func(c *gin.Context) {
+			c.Error(errors.New("image error"))
+		},
+	).Handler(context)
+
+	assert.Equal(t, context.MustGet("user"), "nmarsollier")
+	assert.Equal(t, len(context.Errors), 2)
+	assert.Equal(t, context.IsAborted(), true)
+}
+
+func TestInParallelPanic(t *testing.T) {
+	response := test.ResponseWriter(t)
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/parallel/users/123", nil)
+
+	defer func() {
+		recovered, ok := recover().(*middlewares.RecoveredPanic)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, recovered.Value, "profile failed")
+	}()
+
+	inParallel(
+		func(c *gin.Context) {
+			panic("profile failed")
+		},
+	).Handler(context)
+}
@@ -6,49 +6,85 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/nmarsollier/go_router_builder/model/profile"
 	"github.com/nmarsollier/go_router_builder/model/user"
-	"github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/rest/validation"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+	"github.com/nmarsollier/go_router_builder/utils/ratelimit"
 )
 
-// Servicio REST que nos retorna información de un dialogo a mostrar en pantalla
+// Users servicio REST que nos retorna información de un dialogo a mostrar en pantalla
 // Vamos a usar el contexto como un Builder Pattern
-func init() {
-	router().GET(
-		"/users/:id",
-		validateUserName,
-		fetchUser,
-		fetchProfile,
-		build,
-	)
+var Users = Routes{
+	{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Handlers: Chain(
+			middlewares.RateLimit(usersLimit),
+			fetchUser,
+			fetchProfile,
+			build,
+		),
+		Validate: validation.Rules{
+			validation.Param("id").MinLen(1).Describe("User id"),
+		},
+		Meta: Meta{
+			Summary:  "User and profile information",
+			Tags:     []string{"users"},
+			Response: userAnswer{},
+			Errors:   []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+		},
+	},
 }
 
-// Hacemos las validaciones que nos aseguran que id es valido
-func validateUserName(c *gin.Context) {
-	id := c.Param("id")
+// Cada llamada a los servicios de usuarios consume tiempo de los servicios remotos,
+// todas las rutas y versiones comparten el limite
+var usersLimit = middlewares.RateLimitOptions{
+	Name: "users",
+	Limits: func(c *gin.Context) ratelimit.Limit {
+		limit := config.Get(c).RateLimits.Users
+		return ratelimit.Limit{Requests: limit.Requests, Per: limit.Per.Duration}
+	},
+}
 
-	if len(id) < 1 {
-		c.Error(errors.NewCustomError(400, "userName debe tener al menos 1 caracteres"))
+func fetchUser(c *gin.Context) {
+	data, err := user.FetchUser(c.Request.Context(), config.Get(c).UserURL, c.Param("id"))
+	if err != nil {
+		c.Error(err)
 		c.Abort()
 		return
 	}
-}
 
-func fetchUser(c *gin.Context) {
-	c.Set("user", user.FetchUser())
+	c.Set("user", data)
 	c.Next()
 }
 
 func fetchProfile(c *gin.Context) {
-	c.Set("profile", profile.FetchProfile())
+	data, err := profile.FetchProfile(c.Request.Context(), config.Get(c).ProfileURL, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.Set("profile", data)
 	c.Next()
 }
 
+// Respuesta de los servicios de usuarios
+type userAnswer struct {
+	Access string `json:"access" xml:"access" yaml:"access"`
+	Login  string `json:"login" xml:"login" yaml:"login"`
+	Name   string `json:"name" xml:"name" yaml:"name"`
+}
+
 func build(c *gin.Context) {
 	user := c.MustGet("user").(*user.User)
 	profile := c.MustGet("profile").(*profile.Profile)
 
-	c.JSON(http.StatusOK, gin.H{
-		"login":  user.Login,
-		"access": user.Access,
-		"name":   profile.Name,
+	gu.SendAnswer(c, userAnswer{
+		Access: user.Access,
+		Login:  user.Login,
+		Name:   profile.Name,
 	})
 }
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestUserFromConfiguredServices(t *testing.T) {
+	forwarded := make(chan string, 4)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- r.Header.Get("X-Request-ID")
+		switch r.URL.Path {
+		case "/users/123":
+			w.Write([]byte(`{"login":"jdoe","access":"USER"}`))
+		case "/profiles/123":
+			w.Write([]byte(`{"login":"jdoe","name":"John Doe"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	cfg.UserURL = upstream.URL + "/users"
+	cfg.ProfileURL = upstream.URL + "/profiles"
+
+	engine, err := NewEngine(Options{Config: config.Static(cfg)}, Users, ParallelUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/parallel/users/123", nil)
+	request.Header.Set("X-Request-ID", "req-123")
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("X-Request-ID"), "req-123")
+	assert.Equal(t, <-forwarded, "req-123")
+	assert.Equal(t, <-forwarded, "req-123")
+	assert.Equal(t, response.Body.String(), "{\"access\":\"USER\",\"login\":\"jdoe\",\"name\":\"John Doe\"}")
+
+	response = httptest.NewRecorder()
+	request, _ = http.NewRequest("GET", "/users/456", nil)
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 404)
+}
+
+func TestUpstreamTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer upstream.Close()
+	defer close(hang)
+
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+	cfg.UserURL = upstream.URL + "/users"
+	cfg.RequestTimeout = config.Duration{Duration: 50 * time.Millisecond}
+
+	engine, _ := NewEngine(Options{Config: config.Static(cfg)}, Users)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/users/123", nil)
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 504)
+}
+
+func TestReloadedUsersLimit(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"jdoe"}`))
+	}))
+	defer upstream.Close()
+
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	write := func(requests int) {
+		ioutil.WriteFile(file, []byte(fmt.Sprintf(
+			"user_url: %s\nprofile_url: %s\nreport:\n  output: none\nrate_limits:\n  users:\n    requests: %d\n    per: 1m\n",
+			upstream.URL, upstream.URL, requests,
+		)), 0644)
+	}
+
+	write(1)
+	store, err := config.NewStore([]string{"-config", file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, _ := NewEngine(Options{Config: store}, Users)
+
+	assert.Equal(t, get(engine, "/users/123").Code, 200)
+	assert.Equal(t, get(engine, "/users/123").Code, 429)
+
+	write(5)
+	if err := store.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The drained bucket refills at the new rate
+	assert.Equal(t, get(engine, "/users/123").Header().Get("RateLimit-Limit"), "5")
+}
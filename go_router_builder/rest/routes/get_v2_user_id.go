@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/model/profile"
+	"github.com/nmarsollier/go_router_builder/model/user"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/rest/validation"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+)
+
+// UsersV2 servicio REST de usuarios, los roles se informan como lista.
+// Usa los mismos pasos que v1, solo cambia como se arma la respuesta
+var UsersV2 = Routes{
+	{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Handlers: []Step{
+			Handle(middlewares.RateLimit(usersLimit)),
+			inParallel(
+				fetchUserInParallel,
+				fetchProfileInParallel,
+			),
+			Handle(buildV2),
+		},
+		Validate: validation.Rules{
+			validation.Param("id").MinLen(1).Describe("User id"),
+		},
+		Meta: Meta{
+			Summary:  "User and profile information",
+			Tags:     []string{"users"},
+			Response: userAnswerV2{},
+			Errors:   []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+		},
+	},
+}
+
+// Respuesta de la version 2 de los servicios de usuarios
+type userAnswerV2 struct {
+	Login string   `json:"login" xml:"login" yaml:"login"`
+	Name  string   `json:"name" xml:"name" yaml:"name"`
+	Roles []string `json:"roles" xml:"roles" yaml:"roles"`
+}
+
+func buildV2(c *gin.Context) {
+	user := c.MustGet("user").(*user.User)
+	profile := c.MustGet("profile").(*profile.Profile)
+
+	roles := []string{}
+	for _, role := range strings.Split(user.Access, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	gu.SendAnswer(c, userAnswerV2{
+		Login: user.Login,
+		Name:  profile.Name,
+		Roles: roles,
+	})
+}
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/gu"
+	"github.com/nmarsollier/go_router_builder/utils/health"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
+)
+
+// Health servicio REST de liveness y readiness.
+// /health/live answers while the process can serve requests, /health/ready runs the
+// registry checks and answers 503 when a critical check fails or the server is draining
+func Health(registry *health.Registry, hooks *shutdown.Hooks) Routes {
+	return Routes{
+		{
+			Method: http.MethodGet,
+			Path:   "/health/live",
+			Handlers: Chain(
+				live,
+			),
+			Meta: Meta{
+				Summary:  "Liveness",
+				Tags:     []string{"health"},
+				Response: health.Report{},
+			},
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/health/ready",
+			Handlers: Chain(
+				ready(registry, hooks),
+			),
+			Meta: Meta{
+				Summary:  "Readiness, with the status of each check",
+				Tags:     []string{"health"},
+				Response: health.Report{},
+				Errors:   []int{http.StatusServiceUnavailable},
+			},
+		},
+	}
+}
+
+func live(c *gin.Context) {
+	gu.Render(c, http.StatusOK, health.Report{Status: health.Up})
+}
+
+func ready(registry *health.Registry, hooks *shutdown.Hooks) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if hooks.Draining() {
+			gu.Render(c, http.StatusServiceUnavailable, health.Report{Status: health.Down})
+			return
+		}
+
+		report := registry.Run(c.Request.Context())
+		status := http.StatusOK
+		if report.Status == health.Down {
+			status = http.StatusServiceUnavailable
+		}
+		gu.Render(c, status, report)
+	}
+}
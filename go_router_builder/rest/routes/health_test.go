@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/health"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestHealth(t *testing.T) {
+	hooks := &shutdown.Hooks{}
+	registry := health.NewRegistry(time.Minute)
+	registry.Add(health.Check{Name: "db", Critical: true, Run: func(ctx context.Context) error { return nil }})
+
+	engine, _ := NewEngine(Options{}, Health(registry, hooks))
+
+	assert.Equal(t, get(engine, "/health/live").Code, 200)
+	assert.Equal(t, get(engine, "/health/ready").Code, 200)
+
+	registry.Add(health.Check{Name: "db", Critical: true, Run: func(ctx context.Context) error { return errors.New("down") }})
+	assert.Equal(t, get(engine, "/health/ready").Code, 503)
+
+	registry.Add(health.Check{Name: "db", Critical: true, Run: func(ctx context.Context) error { return nil }})
+	hooks.Drain()
+	response := get(engine, "/health/ready")
+	assert.Equal(t, response.Code, 503)
+	assert.Equal(t, response.Body.String(), `{"status":"down"}`)
+	assert.Equal(t, get(engine, "/health/live").Code, 200)
+}
+
+func TestHealthXML(t *testing.T) {
+	registry := health.NewRegistry(time.Minute)
+	registry.Add(health.Check{Name: "db", Critical: true, Run: func(ctx context.Context) error { return errors.New("down") }})
+
+	engine, _ := NewEngine(Options{}, Health(registry, &shutdown.Hooks{}))
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/health/ready", nil)
+	request.Header.Set("Accept", "application/xml")
+	engine.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 503)
+	assert.Equal(t, strings.HasPrefix(response.Header().Get("Content-Type"), "application/xml"), true)
+	assert.Equal(t, strings.Contains(response.Body.String(), `<status>down</status><check name="db"><status>down</status><critical>true</critical><error>down</error>`), true)
+}
@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+)
+
+// OpenAPIInfo is the api title and version used in the generated document
+var OpenAPIInfo = openapi.Info{
+	Title:   "go_router_builder",
+	Version: "1.0.0",
+}
+
+// OpenAPI generates the OpenAPI 3 document of the modules routes
+func OpenAPI(modules ...Routes) *openapi.Document {
+	var operations []openapi.Route
+	for _, routes := range modules {
+		for _, r := range routes {
+			r = r.documented()
+			operations = append(operations, openapi.Route{
+				Method:     r.Method,
+				Path:       r.Path,
+				Summary:    r.Meta.Summary,
+				Tags:       r.Meta.Tags,
+				Params:     params(r),
+				Response:   r.Meta.Response,
+				Errors:     errorStatuses(r),
+				Deprecated: r.Meta.Deprecated,
+			})
+		}
+	}
+
+	return openapi.Generate(OpenAPIInfo, operations)
+}
+
+// Docs servicio REST que publica el documento OpenAPI de los modulos en /openapi.json
+func Docs(modules ...Routes) Routes {
+	document := OpenAPI(modules...)
+
+	return Routes{
+		{
+			Method: http.MethodGet,
+			Path:   "/openapi.json",
+			Handlers: Chain(
+				openAPI(document),
+			),
+			Meta: Meta{
+				Summary: "OpenAPI document",
+				Tags:    []string{"docs"},
+			},
+		},
+	}
+}
+
+func openAPI(document *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+// The validation rules parameters, with the ones only declared in Meta
+func params(r Route) []openapi.Parameter {
+	result := r.Validate.Parameters()
+	for _, param := range r.Meta.Params {
+		if !hasParam(result, param) {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+func hasParam(params []openapi.Parameter, param openapi.Parameter) bool {
+	for _, p := range params {
+		if p.Name == param.Name && p.In == param.In {
+			return true
+		}
+	}
+	return false
+}
+
+// Routes with validation rules can also answer 400 and 422, routes with roles 401 and 403
+func errorStatuses(r Route) []int {
+	var result []int
+	if len(r.Validate) > 0 {
+		result = append(result, http.StatusBadRequest, http.StatusUnprocessableEntity)
+	}
+	if len(r.Roles) > 0 {
+		result = append(result, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	return append(result, r.Meta.Errors...)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestDocs(t *testing.T) {
+	engine, err := NewEngine(Options{}, Users, Docs(Users))
+	assert.Equal(t, err, nil)
+
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, response.Code, 200)
+
+	document := openapi.Document{}
+	assert.Equal(t, json.Unmarshal(response.Body.Bytes(), &document), nil)
+
+	operation := document.Paths["/users/{id}"]["get"]
+	assert.Equal(t, operation.Summary, "User and profile information")
+	assert.Equal(t, operation.Parameters[0].Name, "id")
+	assert.Equal(t, *operation.Parameters[0].Schema.MinLength, 1)
+	assert.Equal(t, operation.Responses["200"].Content["application/json"].Schema.Properties["login"].Type, "string")
+	assert.Equal(t, operation.Responses["400"].Description, "Bad Request")
+	assert.Equal(t, operation.Responses["422"].Description, "Unprocessable Entity")
+}
@@ -1,22 +1,108 @@
 package routes
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/gin-gonic/gin"
-	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
 )
 
-// Start server in 8080 port
-func Start() {
-	router().Run(":8080")
+// Start server with the given config and modules, reloading the config when it changes.
+// hooks run when the server stops
+func Start(store *config.Store, hooks *shutdown.Hooks, modules ...Routes) {
+	cfg := store.Current()
+	gin.SetMode(cfg.GinMode)
+
+	i18n.SetFallback(cfg.Language)
+	store.Subscribe(func(cfg *config.Config) {
+		i18n.SetFallback(cfg.Language)
+	})
+
+	stopWatch := make(chan struct{})
+	go store.Watch(5*time.Second, stopWatch)
+	hooks.Add("config watcher", func(ctx context.Context) error {
+		close(stopWatch)
+		return nil
+	})
+
+	engine, err := NewEngine(Options{Config: store, Shutdown: hooks, Metrics: true}, modules...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := Serve(fmt.Sprintf(":%d", cfg.Port), engine, cfg.DrainDelay.Duration, cfg.GracePeriod.Duration, cfg.HooksTimeout.Duration, hooks); err != nil {
+		log.Fatal(err)
+	}
 }
 
-var engine *gin.Engine = nil
+// Serve listens in addr until SIGINT or SIGTERM, then keeps serving for drainDelay with
+// hooks draining, stops accepting connections, waits up to gracePeriod for in-flight
+// requests and runs the shutdown hooks for up to hooksTimeout
+func Serve(addr string, handler http.Handler, drainDelay, gracePeriod, hooksTimeout time.Duration, hooks *shutdown.Hooks) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
 
-func router() *gin.Engine {
-	if engine == nil {
-		engine = gin.Default()
-		engine.Use(middlewares.ErrorHandler)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	return serve(listener, handler, stop, drainDelay, gracePeriod, hooksTimeout, hooks)
+}
+
+func serve(
+	listener net.Listener,
+	handler http.Handler,
+	stop <-chan os.Signal,
+	drainDelay time.Duration,
+	gracePeriod time.Duration,
+	hooksTimeout time.Duration,
+	hooks *shutdown.Hooks,
+) error {
+	server := &http.Server{Handler: handler}
+
+	serverError := make(chan error, 1)
+	go func() {
+		serverError <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serverError:
+		return err
+	case sig := <-stop:
+		log.Printf("Signal %s received, shutting down", sig)
+	}
+
+	// Readiness fails from now on, load balancers need some time to notice it
+	hooks.Drain()
+	if drainDelay > 0 {
+		log.Printf("Draining for %s", drainDelay)
+		time.Sleep(drainDelay)
 	}
 
-	return engine
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("In-flight requests did not finish : %s", err.Error())
+	}
+
+	// Hooks get their own deadline, in-flight requests could have used all the grace period
+	hooksCtx, cancelHooks := context.WithTimeout(context.Background(), hooksTimeout)
+	defer cancelHooks()
+
+	hooks.Run(hooksCtx)
+	return err
 }
@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/shutdown"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	var hooks []string
+	shutdownHooks := &shutdown.Hooks{}
+	shutdownHooks.Add("first", func(ctx context.Context) error {
+		hooks = append(hooks, "first")
+		return nil
+	})
+	shutdownHooks.Add("second", func(ctx context.Context) error {
+		hooks = append(hooks, "second")
+		return nil
+	})
+
+	handler := gin.New()
+	handler.GET("/slow", func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- serve(listener, handler, stop, 0, 5*time.Second, 5*time.Second, shutdownHooks)
+	}()
+
+	responses := make(chan string, 1)
+	go func() {
+		response, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+		body, _ := ioutil.ReadAll(response.Body)
+		responses <- string(body)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	stop <- syscall.SIGTERM
+
+	assert.Equal(t, <-responses, "done")
+	assert.Equal(t, <-stopped, nil)
+	assert.Equal(t, hooks, []string{"second", "first"})
+
+	_, err = http.Get("http://" + listener.Addr().String() + "/slow")
+	assert.NotEqual(t, err, nil)
+}
+
+func TestHooksTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	flushed := make(chan bool, 1)
+	hooks := &shutdown.Hooks{}
+	hooks.Add("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	hooks.Add("flush", func(ctx context.Context) error {
+		flushed <- ctx.Err() == nil
+		return nil
+	})
+
+	handler := gin.New()
+	handler.GET("/slow", func(c *gin.Context) {
+		time.Sleep(time.Second)
+		c.Status(http.StatusOK)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- serve(listener, handler, stop, 0, 200*time.Millisecond, 200*time.Millisecond, hooks)
+	}()
+
+	go http.Get("http://" + listener.Addr().String() + "/slow")
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	stop <- syscall.SIGTERM
+
+	// The request uses all the grace period, hooks still get their own time, and no more
+	assert.Equal(t, <-stopped, context.DeadlineExceeded)
+	assert.Equal(t, <-flushed, true)
+	assert.Equal(t, time.Since(start) < 700*time.Millisecond, true)
+}
+
+func TestDrainDelay(t *testing.T) {
+	hooks := &shutdown.Hooks{}
+	handler := gin.New()
+	handler.GET("/ready", func(c *gin.Context) {
+		if hooks.Draining() {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- serve(listener, handler, stop, 300*time.Millisecond, 5*time.Second, 5*time.Second, hooks)
+	}()
+
+	stop <- syscall.SIGTERM
+	time.Sleep(100 * time.Millisecond)
+
+	// Still served while draining, so load balancers see it is not ready
+	response, err := http.Get("http://" + listener.Addr().String() + "/ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	assert.Equal(t, response.StatusCode, http.StatusServiceUnavailable)
+
+	assert.Equal(t, <-stopped, nil)
+}
@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+	"github.com/nmarsollier/go_router_builder/utils/errors"
+)
+
+// VersionHeader is the request header that selects the version of unprefixed paths
+const VersionHeader = "Accept-Version"
+
+// Version is a version of the api, served under the /Name prefix
+type Version struct {
+	Name   string
+	Routes Routes
+	// Deprecation is when the version was deprecated, zero when it is not
+	Deprecation time.Time
+	// Sunset is when the version stops being served, optional
+	Sunset time.Time
+	// Link documents the deprecation and how to migrate, optional
+	Link string
+}
+
+// Versioned serves each version under its prefix, like /v1/users/:id. Unprefixed paths,
+// like /users/:id, serve the version of the Accept-Version header, or the default one.
+// Paths the default version does not declare serve the first version that declares them
+func Versioned(defaultVersion string, versions ...Version) Routes {
+	var result Routes
+
+	// The unprefixed routes, in the order they are first declared
+	var unprefixed []Route
+	byPath := map[string]*pathVersions{}
+	for _, version := range versions {
+		for _, r := range version.Routes {
+			result = append(result, version.prefixed(r))
+
+			key := r.Method + " " + r.Path
+			if byPath[key] == nil {
+				byPath[key] = &pathVersions{routes: map[string]Route{}, chains: map[string][]gin.HandlerFunc{}}
+				unprefixed = append(unprefixed, r)
+			}
+			byPath[key].names = append(byPath[key].names, version.Name)
+			byPath[key].routes[version.Name] = version.prefixed(r)
+			byPath[key].chains[version.Name] = version.prefixed(r).chain()
+		}
+	}
+
+	for _, r := range unprefixed {
+		path := byPath[r.Method+" "+r.Path]
+		path.fallback = defaultVersion
+		if _, ok := path.routes[defaultVersion]; !ok {
+			path.fallback = path.names[0]
+		}
+
+		// The engine adds the handler, with its default timeout
+		route := Route{
+			Method:   r.Method,
+			Path:     r.Path,
+			versions: path,
+		}
+		route.Meta = route.documented().Meta
+		result = append(result, route)
+	}
+
+	return result
+}
+
+// pathVersions are the versions that declare an unprefixed path
+type pathVersions struct {
+	// names of the versions, in the order they are declared
+	names []string
+	// routes of each version, with their prefix
+	routes map[string]Route
+	// chains are the handler chains of the routes
+	chains map[string][]gin.HandlerFunc
+	// fallback is the version served without Accept-Version
+	fallback string
+}
+
+// negotiated is the route of an unprefixed path as the engine serves it, negotiating the version.
+// Versions without their own timeout get defaultTimeout
+func (r Route) negotiated(defaultTimeout time.Duration) Route {
+	if r.versions != nil {
+		r.Handlers = Chain(negotiate(r.versions, defaultTimeout))
+	}
+	return r
+}
+
+// documented is the route of an unprefixed path as the docs show it: the route of the version
+// served without Accept-Version, with the header listing the versions of the path
+func (r Route) documented() Route {
+	if r.versions == nil {
+		return r
+	}
+
+	doc := r.versions.routes[r.versions.fallback]
+	doc.Path = r.Path
+	doc.Meta.Params = append([]openapi.Parameter{{
+		Name:        VersionHeader,
+		In:          "header",
+		Description: "Api version, " + r.versions.fallback + " when not sent",
+		Schema:      &openapi.Schema{Type: "string", Enum: r.versions.names},
+	}}, doc.Meta.Params...)
+	doc.Meta.Errors = append(append([]int{}, doc.Meta.Errors...), http.StatusNotAcceptable)
+	return doc
+}
+
+// prefixed is the route served under the version prefix, with the deprecation headers
+func (v Version) prefixed(r Route) Route {
+	r.Path = "/" + v.Name + r.Path
+	if v.Deprecation.IsZero() {
+		return r
+	}
+
+	headers := map[string]string{}
+	for name, value := range r.Headers {
+		headers[name] = value
+	}
+	headers["Deprecation"] = "@" + strconv.FormatInt(v.Deprecation.Unix(), 10)
+	if !v.Sunset.IsZero() {
+		headers["Sunset"] = v.Sunset.UTC().Format(http.TimeFormat)
+	}
+	if v.Link != "" {
+		headers["Link"] = "<" + v.Link + `>; rel="deprecation"`
+	}
+	r.Headers = headers
+	r.Meta.Deprecated = true
+	return r
+}
+
+// negotiate runs the handler chain of the requested version, with its timeout.
+// Handlers run one after the other, as the branches of inParallel
+func negotiate(path *pathVersions, defaultTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", VersionHeader)
+
+		requested := c.GetHeader(VersionHeader)
+		if requested == "" {
+			requested = path.fallback
+		} else if !strings.HasPrefix(requested, "v") {
+			requested = "v" + requested
+		}
+
+		route, ok := path.routes[requested]
+		if !ok {
+			c.Error(errors.NewLocalizedError(http.StatusNotAcceptable, "unsupported_version",
+				map[string]interface{}{"version": requested}).WithMetadata("versions", path.names))
+			c.Abort()
+			return
+		}
+
+		// The engine sets no deadline for unprefixed routes, each version has its own
+		timeout := route.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Header("Content-Version", requested)
+		for _, handler := range path.chains[requested] {
+			handler(c)
+			if c.IsAborted() {
+				return
+			}
+		}
+	}
+}
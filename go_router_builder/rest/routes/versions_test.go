@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+	"github.com/nmarsollier/go_router_builder/utils/config"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func answer(text string) Routes {
+	return Routes{
+		{
+			Method: http.MethodGet,
+			Path:   "/hello/:name",
+			Handlers: Chain(func(c *gin.Context) {
+				c.String(http.StatusOK, text+" "+c.Param("name"))
+			}),
+		},
+	}
+}
+
+func versionedEngine() *gin.Engine {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+
+	engine, _ := NewEngine(Options{Config: config.Static(cfg)}, Versioned("v1",
+		Version{
+			Name:        "v1",
+			Routes:      answer("hello"),
+			Deprecation: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:      time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+			Link:        "/openapi.json",
+		},
+		Version{
+			Name:   "v2",
+			Routes: answer("hi"),
+		},
+	))
+	return engine
+}
+
+func getVersion(engine *gin.Engine, path, version string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", path, nil)
+	request.Header.Set("X-Request-ID", "req-1")
+	if version != "" {
+		request.Header.Set(VersionHeader, version)
+	}
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+func TestVersionPrefix(t *testing.T) {
+	engine := versionedEngine()
+
+	response := getVersion(engine, "/v1/hello/juan", "")
+	assert.Equal(t, response.Body.String(), "hello juan")
+	assert.Equal(t, response.Header().Get("Deprecation"), "@1790812800")
+	assert.Equal(t, response.Header().Get("Sunset"), "Thu, 01 Apr 2027 00:00:00 GMT")
+	assert.Equal(t, response.Header().Get("Link"), `</openapi.json>; rel="deprecation"`)
+
+	response = getVersion(engine, "/v2/hello/juan", "")
+	assert.Equal(t, response.Body.String(), "hi juan")
+	assert.Equal(t, response.Header().Get("Deprecation"), "")
+}
+
+func TestVersionHeader(t *testing.T) {
+	engine := versionedEngine()
+
+	response := getVersion(engine, "/hello/juan", "")
+	assert.Equal(t, response.Body.String(), "hello juan")
+	assert.Equal(t, response.Header().Get("Content-Version"), "v1")
+	assert.Equal(t, response.Header().Get("Deprecation"), "@1790812800")
+
+	response = getVersion(engine, "/hello/juan", "2")
+	assert.Equal(t, response.Body.String(), "hi juan")
+	assert.Equal(t, response.Header().Get("Content-Version"), "v2")
+	assert.Equal(t, response.Header().Get("Vary"), VersionHeader)
+
+	response = getVersion(engine, "/hello/juan", "v3")
+	assert.Equal(t, response.Code, 406)
+	assert.Equal(t, response.Body.String(),
+		`{"error":"La versión v3 no está disponible","metadata":{"versions":["v1","v2"]},"request_id":"req-1"}`)
+}
+
+func TestVersionsOpenAPI(t *testing.T) {
+	document := OpenAPI(API)
+
+	assert.Equal(t, document.Paths["/v1/users/{id}"]["get"].Deprecated, true)
+	assert.Equal(t, document.Paths["/v2/users/{id}"]["get"].Deprecated, false)
+	assert.Equal(t, document.Paths["/v2/users/{id}"]["get"].Responses["200"].
+		Content["application/json"].Schema.Properties["roles"].Type, "array")
+
+	users := document.Paths["/users/{id}"]["get"]
+	assert.Equal(t, findParameter(users.Parameters, VersionHeader).Schema.Enum, []string{"v1", "v2"})
+	assert.Equal(t, *findParameter(users.Parameters, "id").Schema.MinLength, 1)
+	assert.NotEqual(t, users.Responses["400"], nil)
+	assert.NotEqual(t, users.Responses["406"], nil)
+
+	parallel := document.Paths["/parallel/users/{id}"]["get"]
+	assert.Equal(t, findParameter(parallel.Parameters, VersionHeader).Schema.Enum, []string{"v1"})
+}
+
+func findParameter(params []openapi.Parameter, name string) openapi.Parameter {
+	for _, param := range params {
+		if param.Name == name {
+			return param
+		}
+	}
+	return openapi.Parameter{}
+}
+
+func TestVersionTimeout(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Report.Output = "none"
+
+	deadline := func(c *gin.Context) {
+		deadline, _ := c.Request.Context().Deadline()
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Minute).String())
+	}
+	engine, _ := NewEngine(Options{Config: config.Static(cfg)}, Versioned("v1",
+		Version{Name: "v1", Routes: Routes{{Method: http.MethodGet, Path: "/deadline", Handlers: Chain(deadline)}}},
+		Version{Name: "v2", Routes: Routes{{Method: http.MethodGet, Path: "/deadline", Handlers: Chain(deadline), Timeout: time.Hour}}},
+	))
+
+	assert.Equal(t, getVersion(engine, "/deadline", "v1").Body.String(), "0s")
+	assert.Equal(t, getVersion(engine, "/deadline", "v2").Body.String(), "1h0m0s")
+}
+
+func TestVersionTimeoutReload(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(file, []byte("request_timeout: 1m\nreport:\n  output: none\n"), 0644)
+
+	store, err := config.NewStore([]string{"-config", file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := func(c *gin.Context) {
+		deadline, _ := c.Request.Context().Deadline()
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Minute).String())
+	}
+	engine, _ := NewEngine(Options{Config: store}, Versioned("v1",
+		Version{Name: "v1", Routes: Routes{{Method: http.MethodGet, Path: "/deadline", Handlers: Chain(deadline)}}},
+	))
+
+	ioutil.WriteFile(file, []byte("request_timeout: 2h\nreport:\n  output: none\n"), 0644)
+	assert.Equal(t, store.Reload(), nil)
+
+	// request_timeout is read at startup, by prefixed and unprefixed paths alike
+	assert.Equal(t, getVersion(engine, "/deadline", "").Body.String(), "1m0s")
+	assert.Equal(t, getVersion(engine, "/v1/deadline", "").Body.String(), "1m0s")
+}
+
+func TestVersionFallback(t *testing.T) {
+	engine, _ := NewEngine(Options{}, Versioned("v1",
+		Version{Name: "v1", Routes: answer("hello")},
+		Version{Name: "v2", Routes: Routes{{Method: http.MethodGet, Path: "/bye", Handlers: Chain(
+			func(c *gin.Context) { c.String(http.StatusOK, "bye") },
+		)}}},
+	))
+
+	response := getVersion(engine, "/bye", "")
+	assert.Equal(t, response.Body.String(), "bye")
+	assert.Equal(t, response.Header().Get("Content-Version"), "v2")
+	assert.Equal(t, getVersion(engine, "/bye", "v1").Code, 406)
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+)
+
+// Handler validates the request parameters with the rules.
+// Missing parameters answer 400, parameters that break a rule answer 422, in
+// both cases every violation is listed in the error metadata
+func (rules Rules) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		status := 0
+		violations := []Violation{}
+		for _, rule := range rules {
+			value, sent := lookup(c, rule)
+			for _, violation := range rule.check(value, sent) {
+				violation.Message = i18n.Translate(lang, violation.key, violation.params)
+				violations = append(violations, violation)
+
+				if violation.Rule == "required" {
+					status = http.StatusBadRequest
+				} else if status == 0 {
+					status = http.StatusUnprocessableEntity
+				}
+			}
+		}
+
+		if len(violations) == 0 {
+			return
+		}
+
+		c.Error(errors.NewLocalizedError(status, "invalid_params", nil).WithMetadata("violations", violations))
+		c.Abort()
+	}
+}
+
+func lookup(c *gin.Context, rule *Rule) (string, bool) {
+	switch rule.in {
+	case "query":
+		return c.GetQuery(rule.name)
+	case "header":
+		value := c.GetHeader(rule.name)
+		return value, value != ""
+	default:
+		value := c.Param(rule.name)
+		return value, value != ""
+	}
+}
@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/rest/middlewares"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func serve(rules Rules, url string) *httptest.ResponseRecorder {
+	engine := gin.New()
+	engine.Use(middlewares.ErrorHandler)
+	engine.GET("/profile/:device", rules.Handler(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, url, nil)
+	request.Header.Set("Accept-Language", "en")
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+func TestValidParams(t *testing.T) {
+	rules := Rules{
+		Param("device").OneOf("mobile", "desktop"),
+		Query("userName").MinLen(5).MaxLen(10),
+		Query("page").Optional().Matches(`^\d+$`),
+	}
+
+	response := serve(rules, "/profile/mobile?userName=nestor")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "ok")
+}
+
+func TestInvalidParams(t *testing.T) {
+	rules := Rules{
+		Param("device").OneOf("mobile", "desktop"),
+		Query("userName").MinLen(5),
+		Query("page").Optional().Matches(`^\d+$`),
+	}
+
+	response := serve(rules, "/profile/tv?userName=abc&page=x")
+
+	assert.Equal(t, response.Code, 422)
+	assert.Equal(t, response.Body.String(), `{"error":"Invalid parameters","metadata":{"violations":[`+
+		`{"param":"device","in":"path","rule":"enum","message":"device must be one of: mobile, desktop"},`+
+		`{"param":"userName","in":"query","rule":"minLength","message":"userName must have at least 5 characters"},`+
+		`{"param":"page","in":"query","rule":"pattern","message":"page does not have a valid format"}]}}`)
+}
+
+func TestMissingParam(t *testing.T) {
+	rules := Rules{
+		Query("userName").MinLen(5),
+	}
+
+	response := serve(rules, "/profile/mobile")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Equal(t, response.Body.String(), `{"error":"Invalid parameters","metadata":{"violations":[`+
+		`{"param":"userName","in":"query","rule":"required","message":"userName is required"}]}}`)
+}
+
+func TestParameters(t *testing.T) {
+	params := Rules{
+		Param("device").OneOf("mobile", "desktop"),
+		Query("userName").MinLen(5).Describe("Login"),
+		Query("page").Optional(),
+	}.Parameters()
+
+	assert.Equal(t, params[0].Required, true)
+	assert.Equal(t, params[0].Schema.Enum, []string{"mobile", "desktop"})
+	assert.Equal(t, params[1].Description, "Login")
+	assert.Equal(t, *params[1].Schema.MinLength, 5)
+	assert.Equal(t, params[2].Required, false)
+}
@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nmarsollier/go_router_builder/rest/openapi"
+)
+
+// Rule validates a request parameter
+type Rule struct {
+	name        string
+	in          string
+	description string
+	optional    bool
+	minLen      *int
+	maxLen      *int
+	oneOf       []string
+	pattern     *regexp.Regexp
+}
+
+// Rules are the rules of a route
+type Rules []*Rule
+
+// Param creates a rule for a path parameter
+func Param(name string) *Rule {
+	return &Rule{name: name, in: "path"}
+}
+
+// Query creates a rule for a query parameter, query parameters are required
+// unless Optional is set
+func Query(name string) *Rule {
+	return &Rule{name: name, in: "query"}
+}
+
+// Header creates a rule for a header
+func Header(name string) *Rule {
+	return &Rule{name: name, in: "header"}
+}
+
+// Describe sets the parameter description for the api docs
+func (r *Rule) Describe(description string) *Rule {
+	r.description = description
+	return r
+}
+
+// Optional skips the rules when the parameter is not sent
+func (r *Rule) Optional() *Rule {
+	r.optional = true
+	return r
+}
+
+// MinLen the value must have at least min characters
+func (r *Rule) MinLen(min int) *Rule {
+	r.minLen = &min
+	return r
+}
+
+// MaxLen the value must have at most max characters
+func (r *Rule) MaxLen(max int) *Rule {
+	r.maxLen = &max
+	return r
+}
+
+// OneOf the value must be one of values
+func (r *Rule) OneOf(values ...string) *Rule {
+	r.oneOf = values
+	return r
+}
+
+// Matches the value must match the regular expression, panics if it is not valid
+func (r *Rule) Matches(pattern string) *Rule {
+	r.pattern = regexp.MustCompile(pattern)
+	return r
+}
+
+// Name is the parameter name
+func (r *Rule) Name() string {
+	return r.name
+}
+
+// Violation is a rule a parameter does not satisfy
+type Violation struct {
+	Param   string `json:"param"`
+	In      string `json:"in"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	key     string
+	params  map[string]interface{}
+}
+
+// check validates the value, sent tells if the parameter was in the request
+func (r *Rule) check(value string, sent bool) []Violation {
+	if !sent || value == "" {
+		if r.optional {
+			return nil
+		}
+		return []Violation{r.violation("required", "missing_param", nil)}
+	}
+
+	var result []Violation
+	length := utf8.RuneCountInString(value)
+	if r.minLen != nil && length < *r.minLen {
+		result = append(result, r.violation("minLength", "min_length", map[string]interface{}{"min": *r.minLen}))
+	}
+	if r.maxLen != nil && length > *r.maxLen {
+		result = append(result, r.violation("maxLength", "max_length", map[string]interface{}{"max": *r.maxLen}))
+	}
+	if len(r.oneOf) > 0 && !contains(r.oneOf, value) {
+		result = append(result, r.violation("enum", "one_of", map[string]interface{}{"values": strings.Join(r.oneOf, ", ")}))
+	}
+	if r.pattern != nil && !r.pattern.MatchString(value) {
+		result = append(result, r.violation("pattern", "pattern", nil))
+	}
+	return result
+}
+
+func (r *Rule) violation(rule, key string, params map[string]interface{}) Violation {
+	values := map[string]interface{}{"param": r.name}
+	for k, v := range params {
+		values[k] = v
+	}
+	return Violation{Param: r.name, In: r.in, Rule: rule, key: key, params: values}
+}
+
+// Parameter is the api docs description of the parameter
+func (r *Rule) Parameter() openapi.Parameter {
+	schema := &openapi.Schema{
+		Type:      "string",
+		MinLength: r.minLen,
+		MaxLength: r.maxLen,
+		Enum:      r.oneOf,
+	}
+	if r.pattern != nil {
+		schema.Pattern = r.pattern.String()
+	}
+
+	return openapi.Parameter{
+		Name:        r.name,
+		In:          r.in,
+		Description: r.description,
+		Required:    r.in == "path" || !r.optional,
+		Schema:      schema,
+	}
+}
+
+// Parameters are the api docs description of the rules parameters
+func (rules Rules) Parameters() []openapi.Parameter {
+	result := make([]openapi.Parameter, len(rules))
+	for i, rule := range rules {
+		result[i] = rule.Parameter()
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package accept
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type weightedValue struct {
+	value  string
+	weight float64
+}
+
+// Parse returns the values of an Accept like header, sorted by quality,
+// values with q=0 are not acceptable and are removed
+func Parse(header string) []string {
+	var values []weightedValue
+	for _, v := range parse(header) {
+		if v.weight > 0 {
+			values = append(values, v)
+		}
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].weight > values[j].weight
+	})
+
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.value
+	}
+	return result
+}
+
+// Refused returns the values of an Accept like header with q=0, that a "*" does not include
+func Refused(header string) []string {
+	var result []string
+	for _, v := range parse(header) {
+		if v.weight == 0 {
+			result = append(result, v.value)
+		}
+	}
+	return result
+}
+
+func parse(header string) []weightedValue {
+	var values []weightedValue
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		value := strings.ToLower(strings.TrimSpace(fields[0]))
+		if value == "" {
+			continue
+		}
+
+		weight := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(field, "q=") {
+				if q, err := strconv.ParseFloat(field[2:], 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		values = append(values, weightedValue{value, weight})
+	}
+	return values
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/errors"
+	"github.com/nmarsollier/go_router_builder/utils/requestid"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// GetJSON fetches url and decodes the json response into result, the request id
+// of ctx is forwarded
+func GetJSON(ctx context.Context, url string, result interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		request.Header.Set(requestid.Header, id)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("GET %s: %w", url, errors.ErrNotFound)
+	}
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("GET %s: status %d", url, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+)
+
+// Config is the service configuration
+type Config struct {
+	Port        int      `json:"port" yaml:"port"`
+	GinMode     string   `json:"gin_mode" yaml:"gin_mode"`
+	GracePeriod Duration `json:"grace_period" yaml:"grace_period"`
+	// HooksTimeout is the time the shutdown hooks have after the grace period
+	HooksTimeout Duration `json:"hooks_timeout" yaml:"hooks_timeout"`
+	// DrainDelay keeps serving after a stop signal while readiness fails, so load balancers stop sending requests
+	DrainDelay     Duration   `json:"drain_delay" yaml:"drain_delay"`
+	RequestTimeout Duration   `json:"request_timeout" yaml:"request_timeout"`
+	Language       string     `json:"language" yaml:"language"`
+	UserURL        string     `json:"user_url" yaml:"user_url"`
+	ProfileURL     string     `json:"profile_url" yaml:"profile_url"`
+	Report         Report     `json:"report" yaml:"report"`
+	AccessLog      AccessLog  `json:"access_log" yaml:"access_log"`
+	Auth           Auth       `json:"auth" yaml:"auth"`
+	CORS           CORS       `json:"cors" yaml:"cors"`
+	RateLimits     RateLimits `json:"rate_limits" yaml:"rate_limits"`
+	// TrustForwardedFor takes the client ip from X-Forwarded-For, only behind a proxy that sets it
+	TrustForwardedFor bool `json:"trust_forwarded_for" yaml:"trust_forwarded_for"`
+
+	// File is the config file loaded, if any
+	File string `json:"-" yaml:"-"`
+}
+
+// Report configures where errors are reported
+type Report struct {
+	// Output is stdout, file, sentry or none
+	Output    string `json:"output" yaml:"output"`
+	File      string `json:"file" yaml:"file"`
+	SentryDSN string `json:"sentry_dsn" yaml:"sentry_dsn"`
+	// SampleRates are the fraction of the errors reported by status, statuses without a rate are
+	// reported when they are server errors
+	SampleRates map[int]float64 `json:"sample_rates" yaml:"sample_rates"`
+}
+
+// AccessLog configures the request log
+type AccessLog struct {
+	// Sample is the fraction of successful requests logged, errors and slow requests are always logged
+	Sample float64  `json:"sample" yaml:"sample"`
+	Slow   Duration `json:"slow" yaml:"slow"`
+	// Level is the lowest level logged: info, warn or error
+	Level       string   `json:"level" yaml:"level"`
+	RedactQuery []string `json:"redact_query" yaml:"redact_query"`
+	// Headers are the request headers logged
+	Headers       []string `json:"headers" yaml:"headers"`
+	RedactHeaders []string `json:"redact_headers" yaml:"redact_headers"`
+}
+
+// Auth configures the keys that verify bearer tokens
+type Auth struct {
+	// Secret verifies HS256 tokens
+	Secret string `json:"secret" yaml:"secret"`
+	// JWKSFile has the public keys that verify RS256 and ES256 tokens
+	JWKSFile string `json:"jwks_file" yaml:"jwks_file"`
+	Realm    string `json:"realm" yaml:"realm"`
+}
+
+// CORS configures the cross origin requests
+type CORS struct {
+	// AllowedOrigins are the browser origins allowed, "*" allows any
+	AllowedOrigins   []string `json:"allowed_origins" yaml:"allowed_origins"`
+	AllowCredentials bool     `json:"allow_credentials" yaml:"allow_credentials"`
+	MaxAge           Duration `json:"max_age" yaml:"max_age"`
+}
+
+// RateLimits configures the rate limits of the routes
+type RateLimits struct {
+	// Users is shared by every version of the users routes
+	Users RateLimit `json:"users" yaml:"users"`
+}
+
+// RateLimit allows Requests each Per period
+type RateLimit struct {
+	Requests int      `json:"requests" yaml:"requests"`
+	Per      Duration `json:"per" yaml:"per"`
+}
+
+// Duration is a time.Duration written as "10s" in config files
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText parses durations like "1m30s"
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = value
+	return nil
+}
+
+// MarshalText writes the duration as "1m30s"
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Defaults is the configuration used when nothing else is set
+func Defaults() *Config {
+	return &Config{
+		Port:           8080,
+		GinMode:        gin.DebugMode,
+		GracePeriod:    Duration{10 * time.Second},
+		HooksTimeout:   Duration{5 * time.Second},
+		RequestTimeout: Duration{5 * time.Second},
+		Language:       "es",
+		Report: Report{
+			Output: "stdout",
+		},
+		AccessLog: AccessLog{
+			Sample:        1,
+			Slow:          Duration{time.Second},
+			Level:         "info",
+			RedactQuery:   []string{"token", "password", "api_key"},
+			Headers:       []string{"Accept", "Accept-Language", "Content-Type", "Referer", "User-Agent", "X-Request-ID"},
+			RedactHeaders: []string{"Authorization", "Cookie", "X-Api-Key"},
+		},
+		Auth: Auth{
+			Realm: "go_router_builder",
+		},
+		CORS: CORS{
+			MaxAge: Duration{10 * time.Minute},
+		},
+		RateLimits: RateLimits{
+			Users: RateLimit{Requests: 30, Per: Duration{time.Minute}},
+		},
+	}
+}
+
+// Production is true when the server runs in gin release mode
+func (c *Config) Production() bool {
+	return c.GinMode == gin.ReleaseMode
+}
+
+// Errors are all the problems found validating the config
+type Errors []string
+
+func (e Errors) Error() string {
+	return "invalid config: " + strings.Join(e, ", ")
+}
+
+// Validate checks the whole config, reporting every problem at once
+func (c *Config) Validate() error {
+	var errs Errors
+
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("port %d out of range", c.Port))
+	}
+
+	switch c.GinMode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Sprintf("gin_mode %q must be debug, release or test", c.GinMode))
+	}
+
+	if c.GracePeriod.Duration <= 0 {
+		errs = append(errs, "grace_period must be positive")
+	}
+
+	if c.HooksTimeout.Duration <= 0 {
+		errs = append(errs, "hooks_timeout must be positive")
+	}
+
+	if c.DrainDelay.Duration < 0 {
+		errs = append(errs, "drain_delay can not be negative")
+	}
+
+	if c.RequestTimeout.Duration <= 0 {
+		errs = append(errs, "request_timeout must be positive")
+	}
+
+	if !i18n.IsSupported(c.Language) {
+		errs = append(errs, fmt.Sprintf("language %q not supported", c.Language))
+	}
+
+	for name, value := range map[string]string{"user_url": c.UserURL, "profile_url": c.ProfileURL} {
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("%s %q is not an absolute url", name, value))
+		}
+	}
+
+	switch c.Report.Output {
+	case "stdout", "none":
+	case "file":
+		if c.Report.File == "" {
+			errs = append(errs, "report.file is required for file output")
+		}
+	case "sentry":
+		if c.Report.SentryDSN == "" {
+			errs = append(errs, "report.sentry_dsn is required for sentry output")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("report.output %q must be stdout, file, sentry or none", c.Report.Output))
+	}
+
+	for status, rate := range c.Report.SampleRates {
+		if rate < 0 || rate > 1 {
+			errs = append(errs, fmt.Sprintf("report.sample_rates %d: %v must be between 0 and 1", status, rate))
+		}
+	}
+
+	if c.AccessLog.Sample < 0 || c.AccessLog.Sample > 1 {
+		errs = append(errs, fmt.Sprintf("access_log.sample %v must be between 0 and 1", c.AccessLog.Sample))
+	}
+
+	switch c.AccessLog.Level {
+	case "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Sprintf("access_log.level %q must be info, warn or error", c.AccessLog.Level))
+	}
+
+	if c.RateLimits.Users.Requests < 1 || c.RateLimits.Users.Per.Duration <= 0 {
+		errs = append(errs, "rate_limits.users must allow some requests in a positive period")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaults(t *testing.T) {
+	config, err := load(nil, env(nil))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config, Defaults())
+}
+
+func TestLayers(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+port: 9000
+gin_mode: release
+grace_period: 30s
+report:
+  output: file
+  file: /tmp/errors.log
+`)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	config, err := load(
+		[]string{"-config", file, "-port", "9002"},
+		env(map[string]string{"PORT": "9001", "LANGUAGE": "en"}),
+	)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.Port, 9002)
+	assert.Equal(t, config.Language, "en")
+	assert.Equal(t, config.GinMode, "release")
+	assert.Equal(t, config.GracePeriod.Duration, 30*time.Second)
+	assert.Equal(t, config.Report.File, "/tmp/errors.log")
+	assert.Equal(t, config.Production(), true)
+}
+
+func TestJSONFileFromEnv(t *testing.T) {
+	file := writeFile(t, "config.json", `{"user_url": "http://users.local", "grace_period": "1m"}`)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	config, err := load(nil, env(map[string]string{"CONFIG_FILE": file}))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.UserURL, "http://users.local")
+	assert.Equal(t, config.GracePeriod.Duration, time.Minute)
+}
+
+func TestValidationReportsAllErrors(t *testing.T) {
+	_, err := load(
+		[]string{"-port", "0", "-gin-mode", "fast", "-profile-url", "profiles", "-report", "sentry"},
+		env(map[string]string{"GRACE_PERIOD": "soon", "LANGUAGE": "fr", "ACCESS_LOG_LEVEL": "debug"}),
+	)
+
+	errs, ok := err.(Errors)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, len(errs), 7)
+}
+
+func TestUnknownFileField(t *testing.T) {
+	file := writeFile(t, "config.yml", "prot: 9000\n")
+	defer os.RemoveAll(filepath.Dir(file))
+
+	_, err := load([]string{"-config", file}, env(nil))
+
+	assert.NotEqual(t, err, nil)
+}
+
+func TestAccessLogLists(t *testing.T) {
+	config, err := load(
+		[]string{"-access-log-sample", "0.25"},
+		env(map[string]string{"REDACT_HEADERS": "Authorization, X-Api-Key", "ACCESS_LOG_SLOW": "2s", "ACCESS_LOG_LEVEL": "warn"}),
+	)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.AccessLog.Sample, 0.25)
+	assert.Equal(t, config.AccessLog.Slow.Duration, 2*time.Second)
+	assert.Equal(t, config.AccessLog.Level, "warn")
+	assert.Equal(t, config.AccessLog.RedactHeaders, []string{"Authorization", "X-Api-Key"})
+	assert.Equal(t, config.AccessLog.RedactQuery, []string{"token", "password", "api_key"})
+}
+
+func TestReportSampleRates(t *testing.T) {
+	config, err := load(nil, env(map[string]string{"REPORT_SAMPLE_RATES": "404=0.1, 500=1"}))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.Report.SampleRates, map[int]float64{404: 0.1, 500: 1})
+
+	_, err = load(nil, env(map[string]string{"REPORT_SAMPLE_RATES": "404=2"}))
+	assert.NotEqual(t, err, nil)
+}
@@ -0,0 +1,22 @@
+package config
+
+import "github.com/gin-gonic/gin"
+
+const contextKey = "config"
+
+// Middleware makes the current config available to the handlers with Get,
+// the request keeps the same config even if it is reloaded meanwhile
+func Middleware(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(contextKey, store.Current())
+		c.Next()
+	}
+}
+
+// Get returns the request config, defaults when the middleware is not in the chain
+func Get(c *gin.Context) *Config {
+	if value, ok := c.Get(contextKey); ok {
+		return value.(*Config)
+	}
+	return Defaults()
+}
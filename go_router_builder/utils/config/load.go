@@ -0,0 +1,237 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A value that can be set from env vars and flags
+type binding struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var bindings = []binding{
+	{"port", "PORT", "http port", func(c *Config, value string) (err error) {
+		c.Port, err = strconv.Atoi(value)
+		return
+	}},
+	{"gin-mode", "GIN_MODE", "gin mode: debug, release or test", func(c *Config, value string) error {
+		c.GinMode = value
+		return nil
+	}},
+	{"grace-period", "GRACE_PERIOD", "time to wait for in-flight requests on shutdown", func(c *Config, value string) error {
+		return c.GracePeriod.UnmarshalText([]byte(value))
+	}},
+	{"hooks-timeout", "HOOKS_TIMEOUT", "time to wait for the shutdown hooks", func(c *Config, value string) error {
+		return c.HooksTimeout.UnmarshalText([]byte(value))
+	}},
+	{"drain-delay", "DRAIN_DELAY", "time serving after a stop signal with readiness failing", func(c *Config, value string) error {
+		return c.DrainDelay.UnmarshalText([]byte(value))
+	}},
+	{"request-timeout", "REQUEST_TIMEOUT", "deadline of requests to routes without their own timeout", func(c *Config, value string) error {
+		return c.RequestTimeout.UnmarshalText([]byte(value))
+	}},
+	{"language", "LANGUAGE", "fallback language for messages", func(c *Config, value string) error {
+		c.Language = value
+		return nil
+	}},
+	{"user-url", "USER_URL", "user service url, simulated when empty", func(c *Config, value string) error {
+		c.UserURL = value
+		return nil
+	}},
+	{"profile-url", "PROFILE_URL", "profile service url, simulated when empty", func(c *Config, value string) error {
+		c.ProfileURL = value
+		return nil
+	}},
+	{"report", "REPORT_OUTPUT", "error reporting: stdout, file, sentry or none", func(c *Config, value string) error {
+		c.Report.Output = value
+		return nil
+	}},
+	{"report-file", "REPORT_FILE", "file for file error reporting", func(c *Config, value string) error {
+		c.Report.File = value
+		return nil
+	}},
+	{"sentry-dsn", "SENTRY_DSN", "dsn for sentry error reporting", func(c *Config, value string) error {
+		c.Report.SentryDSN = value
+		return nil
+	}},
+	{"report-sample-rates", "REPORT_SAMPLE_RATES", "comma separated status=rate of the errors reported", func(c *Config, value string) error {
+		rates := map[int]float64{}
+		for _, item := range splitList(value) {
+			parts := strings.SplitN(item, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("%q is not status=rate", item)
+			}
+			status, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+			if err != nil {
+				return err
+			}
+			if rates[status], err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+				return err
+			}
+		}
+		c.Report.SampleRates = rates
+		return nil
+	}},
+	{"access-log-sample", "ACCESS_LOG_SAMPLE", "fraction of successful requests logged", func(c *Config, value string) (err error) {
+		c.AccessLog.Sample, err = strconv.ParseFloat(value, 64)
+		return
+	}},
+	{"access-log-slow", "ACCESS_LOG_SLOW", "requests slower than this are always logged", func(c *Config, value string) error {
+		return c.AccessLog.Slow.UnmarshalText([]byte(value))
+	}},
+	{"access-log-level", "ACCESS_LOG_LEVEL", "lowest level of the requests logged: info, warn or error", func(c *Config, value string) error {
+		c.AccessLog.Level = value
+		return nil
+	}},
+	{"redact-query", "REDACT_QUERY", "comma separated query parameters redacted in logs", func(c *Config, value string) error {
+		c.AccessLog.RedactQuery = splitList(value)
+		return nil
+	}},
+	{"access-log-headers", "ACCESS_LOG_HEADERS", "comma separated request headers logged", func(c *Config, value string) error {
+		c.AccessLog.Headers = splitList(value)
+		return nil
+	}},
+	{"redact-headers", "REDACT_HEADERS", "comma separated headers redacted in logs", func(c *Config, value string) error {
+		c.AccessLog.RedactHeaders = splitList(value)
+		return nil
+	}},
+	{"jwt-secret", "JWT_SECRET", "secret that verifies HS256 tokens", func(c *Config, value string) error {
+		c.Auth.Secret = value
+		return nil
+	}},
+	{"jwks-file", "JWKS_FILE", "jwks file with the keys that verify RS256 and ES256 tokens", func(c *Config, value string) error {
+		c.Auth.JWKSFile = value
+		return nil
+	}},
+	{"cors-origins", "CORS_ORIGINS", "comma separated origins allowed for cross origin requests", func(c *Config, value string) error {
+		c.CORS.AllowedOrigins = splitList(value)
+		return nil
+	}},
+	{"cors-credentials", "CORS_CREDENTIALS", "allow credentials in cross origin requests", func(c *Config, value string) (err error) {
+		c.CORS.AllowCredentials, err = strconv.ParseBool(value)
+		return
+	}},
+	{"users-rate-limit", "USERS_RATE_LIMIT", "requests allowed to each client in the users routes each period", func(c *Config, value string) (err error) {
+		c.RateLimits.Users.Requests, err = strconv.Atoi(value)
+		return
+	}},
+	{"users-rate-limit-per", "USERS_RATE_LIMIT_PER", "period of the users rate limit", func(c *Config, value string) error {
+		return c.RateLimits.Users.Per.UnmarshalText([]byte(value))
+	}},
+	{"trust-forwarded-for", "TRUST_FORWARDED_FOR", "take the client ip from X-Forwarded-For, only behind a trusted proxy", func(c *Config, value string) (err error) {
+		c.TrustForwardedFor, err = strconv.ParseBool(value)
+		return
+	}},
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Load builds the config in layers: defaults, then the yaml or json config file,
+// then env vars and finally command line flags
+func Load(args []string) (*Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+type flagValue struct {
+	value string
+	isSet bool
+}
+
+func (f *flagValue) String() string {
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	f.value = value
+	f.isSet = true
+	return nil
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	flags := flag.NewFlagSet("go_router_builder", flag.ContinueOnError)
+
+	defaultFile, _ := lookupEnv("CONFIG_FILE")
+	file := flags.String("config", defaultFile, "yaml or json config file")
+
+	values := make([]*flagValue, len(bindings))
+	for i, b := range bindings {
+		values[i] = &flagValue{}
+		flags.Var(values[i], b.flag, fmt.Sprintf("%s (env %s)", b.usage, b.env))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := Defaults()
+	if *file != "" {
+		if err := loadFile(config, *file); err != nil {
+			return nil, err
+		}
+		config.File = *file
+	}
+
+	var errs Errors
+	for i, b := range bindings {
+		if value, ok := lookupEnv(b.env); ok {
+			if err := b.set(config, value); err != nil {
+				errs = append(errs, fmt.Sprintf("env %s: %s", b.env, err.Error()))
+			}
+		}
+		if values[i].isSet {
+			if err := b.set(config, values[i].value); err != nil {
+				errs = append(errs, fmt.Sprintf("flag -%s: %s", b.flag, err.Error()))
+			}
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		errs = append(errs, err.(Errors)...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return config, nil
+}
+
+func loadFile(config *Config, file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = json.Unmarshal(data, config)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, config)
+	default:
+		err = fmt.Errorf("unknown config file format %s", file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Store holds the current config, that can be reloaded without restarting the server.
+// Port, gin_mode, grace_period, hooks_timeout, drain_delay, request_timeout, report, access_log
+// headers and redactions, auth, cors and trust_forwarded_for are read only at startup, the other
+// settings change on reload: components read Get(c) on each request or subscribe to the store.
+type Store struct {
+	current     atomic.Value
+	load        func() (*Config, error)
+	mutex       sync.Mutex
+	subscribers []func(*Config)
+}
+
+// NewStore loads the config from args, env and config file, reloads read them again
+func NewStore(args []string) (*Store, error) {
+	return newStore(func() (*Config, error) {
+		return Load(args)
+	})
+}
+
+// Static creates a store that can not be reloaded
+func Static(config *Config) *Store {
+	store := &Store{}
+	store.current.Store(config)
+	return store
+}
+
+func newStore(load func() (*Config, error)) (*Store, error) {
+	config, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{load: load}
+	store.current.Store(config)
+	return store, nil
+}
+
+// Current is the last valid config
+func (s *Store) Current() *Config {
+	return s.current.Load().(*Config)
+}
+
+// Subscribe registers a function called with the new config after each reload
+func (s *Store) Subscribe(subscriber func(*Config)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.subscribers = append(s.subscribers, subscriber)
+}
+
+// Reload loads and validates the config again, an invalid config is rejected
+// and the previous one is kept
+func (s *Store) Reload() error {
+	if s.load == nil {
+		return errors.New("config can not be reloaded")
+	}
+
+	config, err := s.load()
+	if err != nil {
+		log.Printf("Config reload rejected : %s", err.Error())
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.current.Store(config)
+	for _, subscriber := range s.subscribers {
+		subscriber(config)
+	}
+
+	log.Printf("Config reloaded")
+	return nil
+}
+
+// Watch reloads the config on SIGHUP, and when the config file changes,
+// checking it every interval, until stop is closed
+func (s *Store) Watch(interval time.Duration, stop <-chan struct{}) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastModified := s.fileModified()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-hangup:
+			s.Reload()
+			lastModified = s.fileModified()
+		case <-ticker.C:
+			if modified := s.fileModified(); !modified.Equal(lastModified) {
+				lastModified = modified
+				s.Reload()
+			}
+		}
+	}
+}
+
+func (s *Store) fileModified() time.Time {
+	file := s.Current().File
+	if file == "" {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
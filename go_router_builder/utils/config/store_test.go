@@ -0,0 +1,71 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestReload(t *testing.T) {
+	file := writeFile(t, "config.yaml", "language: es\n")
+	defer os.RemoveAll(filepath.Dir(file))
+
+	store, err := newStore(func() (*Config, error) {
+		return load([]string{"-config", file}, env(nil))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var notified []string
+	store.Subscribe(func(config *Config) {
+		notified = append(notified, config.Language)
+	})
+
+	ioutil.WriteFile(file, []byte("language: fr\n"), 0644)
+	assert.NotEqual(t, store.Reload(), nil)
+	assert.Equal(t, store.Current().Language, "es")
+
+	ioutil.WriteFile(file, []byte("language: en\n"), 0644)
+	assert.Equal(t, store.Reload(), nil)
+	assert.Equal(t, store.Current().Language, "en")
+	assert.Equal(t, notified, []string{"en"})
+}
+
+func TestStaticStore(t *testing.T) {
+	store := Static(Defaults())
+
+	assert.NotEqual(t, store.Reload(), nil)
+	assert.Equal(t, store.Current().Port, 8080)
+}
+
+func TestWatch(t *testing.T) {
+	file := writeFile(t, "config.yaml", "port: 9000\n")
+	defer os.RemoveAll(filepath.Dir(file))
+
+	var reloads int32
+	store, _ := newStore(func() (*Config, error) {
+		atomic.AddInt32(&reloads, 1)
+		return load([]string{"-config", file}, env(nil))
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go store.Watch(10*time.Millisecond, stop)
+	time.Sleep(50 * time.Millisecond)
+
+	ioutil.WriteFile(file, []byte("port: 9001\n"), 0644)
+	os.Chtimes(file, time.Now(), time.Now().Add(time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, store.Current().Port, 9001)
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, atomic.LoadInt32(&reloads), int32(3))
+}
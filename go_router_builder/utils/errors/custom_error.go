@@ -1,5 +1,11 @@
 package errors
 
+import (
+	"net/http"
+
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+)
+
 // NewCustomError creates a new custom error
 func NewCustomError(status int, message string) *CustomError {
 	return &CustomError{
@@ -8,10 +14,23 @@ func NewCustomError(status int, message string) *CustomError {
 	}
 }
 
+// NewLocalizedError creates a custom error whose message is resolved from the i18n bundles
+func NewLocalizedError(status int, key string, params map[string]interface{}) *CustomError {
+	return &CustomError{
+		code:   status,
+		key:    key,
+		params: params,
+	}
+}
+
 // CustomError es una interfaz para definir errores custom
 type CustomError struct {
-	code    int
-	message string
+	code     int
+	message  string
+	key      string
+	params   map[string]interface{}
+	headers  http.Header
+	metadata map[string]interface{}
 }
 
 // Code http error code
@@ -21,5 +40,46 @@ func (e *CustomError) Code() int {
 
 // Message http error message
 func (e *CustomError) Error() string {
+	if e.key != "" {
+		return i18n.Translate(i18n.Fallback(), e.key, e.params)
+	}
 	return e.message
 }
+
+// MessageKey i18n message key, empty when the message is not localized
+func (e *CustomError) MessageKey() string {
+	return e.key
+}
+
+// MessageParams values to interpolate into the localized message
+func (e *CustomError) MessageParams() map[string]interface{} {
+	return e.params
+}
+
+// WithHeader adds a header to send in the error response
+func (e *CustomError) WithHeader(key, value string) *CustomError {
+	if e.headers == nil {
+		e.headers = http.Header{}
+	}
+	e.headers.Add(key, value)
+	return e
+}
+
+// WithMetadata adds a value to send in the error response body
+func (e *CustomError) WithMetadata(key string, value interface{}) *CustomError {
+	if e.metadata == nil {
+		e.metadata = map[string]interface{}{}
+	}
+	e.metadata[key] = value
+	return e
+}
+
+// Headers response headers
+func (e *CustomError) Headers() http.Header {
+	return e.headers
+}
+
+// Metadata additional response values
+func (e *CustomError) Metadata() map[string]interface{} {
+	return e.metadata
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewRateLimited creates a 429 error, telling the client when to retry
+func NewRateLimited(retryAfter time.Duration) *CustomError {
+	seconds := retrySeconds(retryAfter)
+	return NewLocalizedError(http.StatusTooManyRequests, "rate_limited", nil).
+		WithHeader("Retry-After", strconv.Itoa(seconds)).
+		WithMetadata("retry_after", seconds)
+}
+
+// NewUnauthorized creates a 401 error, with the bearer authentication challenge
+func NewUnauthorized(realm string) *CustomError {
+	return NewLocalizedError(http.StatusUnauthorized, "unauthorized", nil).
+		WithHeader("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", realm))
+}
+
+// NewForbidden creates a 403 error, listing the roles the operation requires
+func NewForbidden(roles ...string) *CustomError {
+	return NewLocalizedError(http.StatusForbidden, "forbidden", nil).
+		WithMetadata("required_roles", roles)
+}
+
+// NewServiceUnavailable creates a 503 error, telling the client when to retry
+func NewServiceUnavailable(retryAfter time.Duration) *CustomError {
+	seconds := retrySeconds(retryAfter)
+	return NewLocalizedError(http.StatusServiceUnavailable, "service_unavailable", nil).
+		WithHeader("Retry-After", strconv.Itoa(seconds)).
+		WithMetadata("retry_after", seconds)
+}
+
+// NewConflict creates a 409 error for the given resource
+func NewConflict(resource string) *CustomError {
+	return NewLocalizedError(http.StatusConflict, "conflict", map[string]interface{}{"resource": resource}).
+		WithMetadata("resource", resource)
+}
+
+func retrySeconds(retryAfter time.Duration) int {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
@@ -0,0 +1,6 @@
+package errors
+
+import "errors"
+
+// ErrNotFound is returned by services and DAOs when the requested entity does not exist
+var ErrNotFound = errors.New("not found")
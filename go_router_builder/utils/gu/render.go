@@ -0,0 +1,101 @@
+package gu
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/nmarsollier/go_router_builder/utils/accept"
+	"github.com/nmarsollier/go_router_builder/utils/i18n"
+)
+
+type encoder struct {
+	mediaType string
+	render    func(c *gin.Context, status int, data interface{})
+}
+
+func renderJSON(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, data)
+}
+
+func renderXML(c *gin.Context, status int, data interface{}) {
+	c.XML(status, data)
+}
+
+func renderYAML(c *gin.Context, status int, data interface{}) {
+	c.YAML(status, data)
+}
+
+func renderMsgPack(c *gin.Context, status int, data interface{}) {
+	c.Render(status, render.MsgPack{Data: data})
+}
+
+// The first one is the default encoder
+var encoders = []encoder{
+	{gin.MIMEJSON, renderJSON},
+	{gin.MIMEXML, renderXML},
+	{gin.MIMEXML2, renderXML},
+	{"application/yaml", renderYAML},
+	{gin.MIMEYAML, renderYAML},
+	{"text/yaml", renderYAML},
+	{binding.MIMEMSGPACK2, renderMsgPack},
+	{binding.MIMEMSGPACK, renderMsgPack},
+}
+
+// Render sends data with the encoder negotiated from the Accept header,
+// answers 406 when no supported media type is acceptable
+func Render(c *gin.Context, status int, data interface{}) {
+	encoder, ok := negotiate(c)
+	if !ok {
+		NotAcceptable(c)
+		return
+	}
+
+	encoder.render(c, status, data)
+}
+
+// NotAcceptable aborts with 406, when the client accepts none of the supported media types
+func NotAcceptable(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+		"error": i18n.Translate(i18n.Negotiate(c.GetHeader("Accept-Language")), "not_acceptable", nil),
+	})
+}
+
+// SendAnswer sends a 200 response with the negotiated encoder
+func SendAnswer(c *gin.Context, data interface{}) {
+	Render(c, http.StatusOK, data)
+}
+
+// IsAcceptable checks if the client accepts any of the supported media types
+func IsAcceptable(c *gin.Context) bool {
+	_, ok := negotiate(c)
+	return ok
+}
+
+func negotiate(c *gin.Context) (encoder, bool) {
+	if c.Request == nil || c.GetHeader("Accept") == "" {
+		return encoders[0], true
+	}
+
+	for _, mediaRange := range accept.Parse(c.GetHeader("Accept")) {
+		for _, e := range encoders {
+			if matches(mediaRange, e.mediaType) {
+				return e, true
+			}
+		}
+	}
+	return encoder{}, false
+}
+
+func matches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
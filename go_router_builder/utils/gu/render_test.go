@@ -0,0 +1,62 @@
+package gu
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func renderWithAccept(acceptHeader string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(response)
+	context.Request, _ = http.NewRequest("GET", "/users/123", nil)
+	if acceptHeader != "" {
+		context.Request.Header.Set("Accept", acceptHeader)
+	}
+
+	SendAnswer(context, gin.H{"login": "nmarsollier"})
+	return response
+}
+
+func TestRenderJSON(t *testing.T) {
+	for _, header := range []string{"", "*/*", "application/json", "text/html, application/*;q=0.8"} {
+		response := renderWithAccept(header)
+		assert.Equal(t, response.Code, 200)
+		assert.Equal(t, response.Header().Get("Content-Type"), "application/json; charset=utf-8")
+		assert.Equal(t, response.Body.String(), "{\"login\":\"nmarsollier\"}")
+	}
+}
+
+func TestRenderXML(t *testing.T) {
+	response := renderWithAccept("application/json;q=0.5, application/xml")
+
+	assert.Equal(t, response.Header().Get("Content-Type"), "application/xml; charset=utf-8")
+	assert.Equal(t, response.Body.String(), "<map><login>nmarsollier</login></map>")
+}
+
+func TestRenderYAML(t *testing.T) {
+	response := renderWithAccept("application/yaml")
+
+	assert.Equal(t, response.Body.String(), "login: nmarsollier\n")
+}
+
+func TestRenderMsgPack(t *testing.T) {
+	response := renderWithAccept("application/x-msgpack")
+
+	var data map[string]interface{}
+	codec.NewDecoderBytes(response.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&data)
+
+	assert.Equal(t, response.Header().Get("Content-Type"), "application/msgpack; charset=utf-8")
+	assert.Equal(t, string(data["login"].([]byte)), "nmarsollier")
+}
+
+func TestRenderNotAcceptable(t *testing.T) {
+	response := renderWithAccept("text/html, application/json;q=0")
+
+	assert.Equal(t, response.Code, 406)
+	assert.Equal(t, response.Body.String(), "{\"error\":\"Ninguno de los formatos aceptados está disponible\"}")
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/url"
+)
+
+// Reachable checks that a tcp connection can be opened to the host of rawURL,
+// an empty url is always reachable
+func Reachable(ctx context.Context, rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := parsed.Host
+	if parsed.Port() == "" {
+		port := "80"
+		if parsed.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
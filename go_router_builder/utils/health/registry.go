@@ -0,0 +1,195 @@
+package health
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status of a check or of the service
+type Status string
+
+const (
+	// Up everything works
+	Up Status = "up"
+	// Degraded a non critical check fails, the service can still answer
+	Degraded Status = "degraded"
+	// Down a critical check fails or the service is draining
+	Down Status = "down"
+)
+
+// DefaultTimeout is the timeout of checks that do not set one
+const DefaultTimeout = 2 * time.Second
+
+// Check is a named readiness check
+type Check struct {
+	Name string
+	// Timeout to wait for Run, DefaultTimeout when zero
+	Timeout time.Duration
+	// Critical checks take the service down when they fail, others degrade it
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// Result is the last run of a check
+type Result struct {
+	Status   Status    `json:"status" xml:"status" yaml:"status"`
+	Critical bool      `json:"critical" xml:"critical" yaml:"critical"`
+	Error    string    `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+	Checked  time.Time `json:"checked" xml:"checked" yaml:"checked"`
+	Duration string    `json:"duration" xml:"duration" yaml:"duration"`
+}
+
+// Report is the service status with the result of each check
+type Report struct {
+	Status Status            `json:"status" yaml:"status"`
+	Checks map[string]Result `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// MarshalXML writes the checks as check elements sorted by name, xml has no maps
+func (r Report) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type check struct {
+		Name string `xml:"name,attr"`
+		Result
+	}
+	value := struct {
+		Status Status  `xml:"status"`
+		Checks []check `xml:"check"`
+	}{Status: r.Status}
+
+	for name, result := range r.Checks {
+		value.Checks = append(value.Checks, check{Name: name, Result: result})
+	}
+	sort.Slice(value.Checks, func(i, j int) bool {
+		return value.Checks[i].Name < value.Checks[j].Name
+	})
+
+	return e.EncodeElement(value, start)
+}
+
+// ErrTimeout is the error of checks that do not finish in time
+var ErrTimeout = errors.New("check timed out")
+
+var now = time.Now
+
+// Registry runs the checks, caching the results for ttl
+type Registry struct {
+	ttl   time.Duration
+	mutex sync.Mutex
+	// checks in registration order
+	checks []Check
+	cache  map[string]Result
+}
+
+// NewRegistry creates a registry that caches results for ttl
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{
+		ttl:   ttl,
+		cache: map[string]Result{},
+	}
+}
+
+// Add registers a check, replacing the one with the same name
+func (r *Registry) Add(check Check) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.cache, check.Name)
+	for i, c := range r.checks {
+		if c.Name == check.Name {
+			r.checks[i] = check
+			return
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// Run runs the checks in parallel, checks with a cached result are not run
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mutex.Lock()
+	checks := append([]Check{}, r.checks...)
+	r.mutex.Unlock()
+
+	results := make([]Result, len(checks))
+	wg := sync.WaitGroup{}
+	for i, check := range checks {
+		if cached, ok := r.cached(check.Name); ok {
+			results[i] = cached
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: Up, Checks: map[string]Result{}}
+	for i, check := range checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status == Up {
+			continue
+		}
+		if check.Critical {
+			report.Status = Down
+		} else if report.Status == Up {
+			report.Status = Degraded
+		}
+	}
+	return report
+}
+
+func (r *Registry) cached(name string) (Result, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result, ok := r.cache[name]
+	if !ok || now().Sub(result.Checked) >= r.ttl {
+		return Result{}, false
+	}
+	return result, true
+}
+
+func (r *Registry) run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	result := Result{
+		Status:   Up,
+		Critical: check.Critical,
+		Checked:  start,
+		Duration: now().Sub(start).String(),
+	}
+	if err != nil {
+		result.Status = Down
+		result.Error = err.Error()
+	}
+
+	r.mutex.Lock()
+	r.cache[check.Name] = result
+	r.mutex.Unlock()
+
+	return result
+}
@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestStatus(t *testing.T) {
+	registry := NewRegistry(time.Minute)
+	registry.Add(Check{Name: "cache", Run: func(ctx context.Context) error { return nil }})
+
+	assert.Equal(t, registry.Run(context.Background()).Status, Up)
+
+	registry.Add(Check{Name: "metrics", Run: func(ctx context.Context) error { return errors.New("unavailable") }})
+	report := registry.Run(context.Background())
+	assert.Equal(t, report.Status, Degraded)
+	assert.Equal(t, report.Checks["metrics"].Error, "unavailable")
+
+	registry.Add(Check{Name: "db", Critical: true, Run: func(ctx context.Context) error { return errors.New("unavailable") }})
+	assert.Equal(t, registry.Run(context.Background()).Status, Down)
+}
+
+func TestTimeout(t *testing.T) {
+	registry := NewRegistry(time.Minute)
+	registry.Add(Check{
+		Name:     "slow",
+		Timeout:  10 * time.Millisecond,
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	report := registry.Run(context.Background())
+	assert.Equal(t, report.Status, Down)
+	assert.Equal(t, report.Checks["slow"].Error, ErrTimeout.Error())
+}
+
+func TestCache(t *testing.T) {
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	runs := 0
+	registry := NewRegistry(time.Second)
+	registry.Add(Check{Name: "counter", Run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}})
+
+	registry.Run(context.Background())
+	registry.Run(context.Background())
+	assert.Equal(t, runs, 1)
+
+	current = current.Add(time.Second)
+	registry.Run(context.Background())
+	assert.Equal(t, runs, 2)
+}
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nmarsollier/go_router_builder/utils/accept"
+)
+
+// InternalServerError is the message key used for unexpected errors
+const InternalServerError = "internal_server_error"
+
+// RequestError is the message key used for errors that can not be detailed to clients
+const RequestError = "request_error"
+
+// Bundle maps message keys to localized messages
+type Bundle map[string]string
+
+var bundles = map[string]Bundle{
+	"es": es,
+	"en": en,
+}
+
+// fallback changes with config reloads while requests read it
+var fallback atomic.Value
+
+func init() {
+	fallback.Store("es")
+}
+
+// SetFallback sets the language used when the client does not ask for a supported one
+func SetFallback(lang string) {
+	if IsSupported(lang) {
+		fallback.Store(lang)
+	}
+}
+
+// Fallback is the language used when no supported language is requested
+func Fallback() string {
+	return fallback.Load().(string)
+}
+
+// Negotiate picks the best supported language from an Accept-Language header
+func Negotiate(acceptLanguage string) string {
+	for _, tag := range accept.Parse(acceptLanguage) {
+		if tag == "*" {
+			continue
+		}
+		if _, ok := bundles[tag]; ok {
+			return tag
+		}
+
+		base := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := bundles[base]; ok {
+			return base
+		}
+	}
+
+	return Fallback()
+}
+
+// Translate resolves the message key in lang, interpolating {param} placeholders
+func Translate(lang, key string, params map[string]interface{}) string {
+	message, ok := bundles[lang][key]
+	if !ok {
+		message, ok = bundles[Fallback()][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(params) == 0 {
+		return message
+	}
+
+	replace := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replace = append(replace, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replace...).Replace(message)
+}
+
+// IsSupported checks if there are messages for lang
+func IsSupported(lang string) bool {
+	_, ok := bundles[lang]
+	return ok
+}
@@ -0,0 +1,32 @@
+package i18n
+
+import (
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, Negotiate(""), "es")
+	assert.Equal(t, Negotiate("en"), "en")
+	assert.Equal(t, Negotiate("en-GB,en;q=0.9"), "en")
+	assert.Equal(t, Negotiate("fr;q=1, es;q=0.4, en;q=0.6"), "en")
+	assert.Equal(t, Negotiate("en;q=0, fr"), "es")
+}
+
+func TestSetFallback(t *testing.T) {
+	defer SetFallback(Fallback())
+
+	SetFallback("en")
+	assert.Equal(t, Negotiate("fr"), "en")
+
+	SetFallback("xx")
+	assert.Equal(t, Fallback(), "en")
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, Translate("en", "min_length", map[string]interface{}{"param": "id", "min": 5}),
+		"id must have at least 5 characters")
+	assert.Equal(t, Translate("fr", InternalServerError, nil), "Error interno del servidor")
+	assert.Equal(t, Translate("en", "unknown_key", nil), "unknown_key")
+}
@@ -0,0 +1,19 @@
+package i18n
+
+var en = Bundle{
+	InternalServerError:   "Internal Server Error",
+	RequestError:          "The request could not be processed",
+	"rate_limited":        "Too many requests, retry later",
+	"unauthorized":        "Authentication required",
+	"forbidden":           "You do not have permission for this operation",
+	"service_unavailable": "Service unavailable, retry later",
+	"conflict":            "Conflict updating {resource}",
+	"not_acceptable":      "None of the accepted formats is available",
+	"invalid_params":      "Invalid parameters",
+	"missing_param":       "{param} is required",
+	"min_length":          "{param} must have at least {min} characters",
+	"max_length":          "{param} must have at most {max} characters",
+	"one_of":              "{param} must be one of: {values}",
+	"pattern":             "{param} does not have a valid format",
+	"unsupported_version": "Version {version} is not available",
+}
@@ -0,0 +1,19 @@
+package i18n
+
+var es = Bundle{
+	InternalServerError:   "Error interno del servidor",
+	RequestError:          "No se pudo procesar la solicitud",
+	"rate_limited":        "Demasiadas solicitudes, reintente más tarde",
+	"unauthorized":        "Se requiere autenticación",
+	"forbidden":           "No tiene permisos para esta operación",
+	"service_unavailable": "Servicio no disponible, reintente más tarde",
+	"conflict":            "Conflicto al modificar {resource}",
+	"not_acceptable":      "Ninguno de los formatos aceptados está disponible",
+	"invalid_params":      "Parámetros inválidos",
+	"missing_param":       "{param} es requerido",
+	"min_length":          "{param} debe tener al menos {min} caracteres",
+	"max_length":          "{param} debe tener como máximo {max} caracteres",
+	"one_of":              "{param} debe ser uno de: {values}",
+	"pattern":             "{param} no tiene un formato válido",
+	"unsupported_version": "La versión {version} no está disponible",
+}
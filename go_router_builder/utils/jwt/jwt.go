@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Errors returned by Verify
+var (
+	ErrMalformed   = errors.New("malformed token")
+	ErrAlgorithm   = errors.New("unsupported token algorithm")
+	ErrUnknownKey  = errors.New("unknown token key")
+	ErrSignature   = errors.New("invalid token signature")
+	ErrExpired     = errors.New("token expired")
+	ErrNotYetValid = errors.New("token not yet valid")
+)
+
+// Leeway tolerates clock differences with the token issuer
+const Leeway = 30 * time.Second
+
+var now = time.Now
+
+// Claims are the token payload
+type Claims map[string]interface{}
+
+// Subject is the sub claim
+func (c Claims) Subject() string {
+	value, _ := c["sub"].(string)
+	return value
+}
+
+// Keys verify tokens, Secret for HS256 and the public keys by kid for RS256 and ES256
+type Keys struct {
+	Secret []byte
+	Public map[string]crypto.PublicKey
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the token signature and time claims, and returns its claims
+func Verify(token string, keys *Keys) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	h := header{}
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, ErrMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	if err := verifySignature(h, parts[0]+"."+parts[1], signature, keys); err != nil {
+		return nil, err
+	}
+
+	claims := Claims{}
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, ErrMalformed
+	}
+
+	current := now()
+	if exp, ok := claims["exp"].(float64); ok && current.After(unix(exp).Add(Leeway)) {
+		return nil, ErrExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && current.Add(Leeway).Before(unix(nbf)) {
+		return nil, ErrNotYetValid
+	}
+
+	return claims, nil
+}
+
+func verifySignature(h header, signed string, signature []byte, keys *Keys) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch h.Alg {
+	case "HS256":
+		if len(keys.Secret) == 0 {
+			return ErrUnknownKey
+		}
+		mac := hmac.New(sha256.New, keys.Secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrSignature
+		}
+		return nil
+	case "RS256":
+		key, ok := publicKey(keys, h.Kid, isRSA).(*rsa.PublicKey)
+		if !ok {
+			return ErrUnknownKey
+		}
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return ErrSignature
+		}
+		return nil
+	case "ES256":
+		key, ok := publicKey(keys, h.Kid, isECDSA).(*ecdsa.PublicKey)
+		if !ok {
+			return ErrUnknownKey
+		}
+		if len(signature) != 64 {
+			return ErrSignature
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return ErrSignature
+		}
+		return nil
+	default:
+		return ErrAlgorithm
+	}
+}
+
+// The key with kid, tokens without kid use the only key of the right type
+func publicKey(keys *Keys, kid string, matches func(crypto.PublicKey) bool) crypto.PublicKey {
+	if kid != "" {
+		return keys.Public[kid]
+	}
+
+	var found crypto.PublicKey
+	for _, key := range keys.Public {
+		if matches(key) {
+			if found != nil {
+				return nil
+			}
+			found = key
+		}
+	}
+	return found
+}
+
+func isRSA(key crypto.PublicKey) bool {
+	_, ok := key.(*rsa.PublicKey)
+	return ok
+}
+
+func isECDSA(key crypto.PublicKey) bool {
+	_, ok := key.(*ecdsa.PublicKey)
+	return ok
+}
+
+func decodeJSON(part string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func unix(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0)
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func encode(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func sign(t *testing.T, alg, kid string, key interface{}, claims Claims) string {
+	signed := encode(map[string]string{"alg": alg, "kid": kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(pad(r), pad(s)...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func pad(value *big.Int) []byte {
+	data := value.Bytes()
+	return append(make([]byte, 32-len(data)), data...)
+}
+
+func b64(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func TestHS256(t *testing.T) {
+	keys := &Keys{Secret: []byte("secret")}
+	token := sign(t, "HS256", "", []byte("secret"), Claims{"sub": "nmarsollier", "access": "ADMIN,USER"})
+
+	claims, err := Verify(token, keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims.Subject(), "nmarsollier")
+
+	_, err = Verify(sign(t, "HS256", "", []byte("other"), Claims{}), keys)
+	assert.Equal(t, err, ErrSignature)
+
+	_, err = Verify(encode(map[string]string{"alg": "none"})+"."+encode(Claims{})+".", keys)
+	assert.Equal(t, err, ErrAlgorithm)
+
+	_, err = Verify("garbage", keys)
+	assert.Equal(t, err, ErrMalformed)
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	public, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "shared", "k": "c2VjcmV0"},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AA", "y": "AA"}
+	]}`, b64(rsaKey.N), b64(big.NewInt(int64(rsaKey.E))), b64(ecKey.X), b64(ecKey.Y))))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(public), 2)
+	keys := &Keys{Public: public}
+
+	claims, err := Verify(sign(t, "RS256", "rsa", rsaKey, Claims{"sub": "rsa-user"}), keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims.Subject(), "rsa-user")
+
+	claims, err = Verify(sign(t, "ES256", "", ecKey, Claims{"sub": "ec-user"}), keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims.Subject(), "ec-user")
+
+	_, err = Verify(sign(t, "RS256", "ec", rsaKey, Claims{}), keys)
+	assert.Equal(t, err, ErrUnknownKey)
+
+	_, err = Verify(sign(t, "HS256", "", []byte("secret"), Claims{}), keys)
+	assert.Equal(t, err, ErrUnknownKey)
+}
+
+func TestTimeClaims(t *testing.T) {
+	keys := &Keys{Secret: []byte("secret")}
+	current := time.Now()
+
+	_, err := Verify(sign(t, "HS256", "", keys.Secret, Claims{"exp": current.Add(-time.Minute).Unix()}), keys)
+	assert.Equal(t, err, ErrExpired)
+
+	_, err = Verify(sign(t, "HS256", "", keys.Secret, Claims{"exp": current.Add(-10 * time.Second).Unix()}), keys)
+	assert.Equal(t, err, nil)
+
+	_, err = Verify(sign(t, "HS256", "", keys.Secret, Claims{"nbf": current.Add(time.Minute).Unix()}), keys)
+	assert.Equal(t, err, ErrNotYetValid)
+}
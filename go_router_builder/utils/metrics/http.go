@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the latency histogram buckets, in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Labels identify a series
+type Labels struct {
+	Method string
+	Route  string
+	Status string
+}
+
+type series struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// HTTP records request counters, latency histograms and the requests in flight
+type HTTP struct {
+	buckets  []float64
+	mutex    sync.Mutex
+	series   map[Labels]*series
+	inFlight int64
+}
+
+// NewHTTP creates the http metrics with DefaultBuckets
+func NewHTTP() *HTTP {
+	return &HTTP{
+		buckets: DefaultBuckets,
+		series:  map[Labels]*series{},
+	}
+}
+
+// Start counts a request in flight, the returned func records it when it finishes
+func (h *HTTP) Start() func(labels Labels, duration time.Duration) {
+	h.mutex.Lock()
+	h.inFlight++
+	h.mutex.Unlock()
+
+	return func(labels Labels, duration time.Duration) {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		h.inFlight--
+
+		s, ok := h.series[labels]
+		if !ok {
+			s = &series{buckets: make([]uint64, len(h.buckets))}
+			h.series[labels] = s
+		}
+
+		seconds := duration.Seconds()
+		s.count++
+		s.sum += seconds
+		for i, bound := range h.buckets {
+			if seconds <= bound {
+				s.buckets[i]++
+			}
+		}
+	}
+}
+
+// StatusClass groups statuses like 2xx, 4xx
+func StatusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// WriteText writes the metrics in the Prometheus text format
+func (h *HTTP) WriteText(w io.Writer) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]Labels, 0, len(h.series))
+	for labels := range h.series {
+		keys = append(keys, labels)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	b := &strings.Builder{}
+
+	b.WriteString("# HELP http_requests_total Requests served, by method, route and status class.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	for _, labels := range keys {
+		fmt.Fprintf(b, "http_requests_total{%s} %d\n", labels, h.series[labels].count)
+	}
+
+	b.WriteString("# HELP http_request_duration_seconds Request latency, by method, route and status class.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, labels := range keys {
+		s := h.series[labels]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(b, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), s.buckets[i])
+		}
+		fmt.Fprintf(b, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, s.count)
+		fmt.Fprintf(b, "http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "http_request_duration_seconds_count{%s} %d\n", labels, s.count)
+	}
+
+	b.WriteString("# HELP http_requests_in_flight Requests being served.\n")
+	b.WriteString("# TYPE http_requests_in_flight gauge\n")
+	fmt.Fprintf(b, "http_requests_in_flight %d\n", h.inFlight)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (l Labels) String() string {
+	return fmt.Sprintf("method=%s,route=%s,status=%s", quote(l.Method), quote(l.Route), quote(l.Status))
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(value string) string {
+	return `"` + escaper.Replace(value) + `"`
+}
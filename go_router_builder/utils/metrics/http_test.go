@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestWriteText(t *testing.T) {
+	recorder := NewHTTP()
+	recorder.buckets = []float64{.1, 1}
+
+	recorder.Start()(Labels{Method: "GET", Route: "/users/:id", Status: "2xx"}, 50*time.Millisecond)
+	recorder.Start()(Labels{Method: "GET", Route: "/users/:id", Status: "2xx"}, 500*time.Millisecond)
+	recorder.Start()
+
+	text := &strings.Builder{}
+	assert.Equal(t, recorder.WriteText(text), nil)
+
+	assert.Equal(t, text.String(), `# HELP http_requests_total Requests served, by method, route and status class.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/users/:id",status="2xx"} 2
+# HELP http_request_duration_seconds Request latency, by method, route and status class.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="2xx",le="0.1"} 1
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="2xx",le="1"} 2
+http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="2xx",le="+Inf"} 2
+http_request_duration_seconds_sum{method="GET",route="/users/:id",status="2xx"} 0.55
+http_request_duration_seconds_count{method="GET",route="/users/:id",status="2xx"} 2
+# HELP http_requests_in_flight Requests being served.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight 1
+`)
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, StatusClass(200), "2xx")
+	assert.Equal(t, StatusClass(404), "4xx")
+	assert.Equal(t, StatusClass(503), "5xx")
+}
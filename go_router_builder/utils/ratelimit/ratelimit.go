@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests every Per, in bursts of up to Requests
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Result is the state of a bucket after taking a token
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero when allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets, a shared store lets several instances enforce the same limits
+type Store interface {
+	Take(key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket refills, from then on it is the same as a new one
+	full time.Time
+}
+
+var now = time.Now
+
+// MemoryStore keeps token buckets in memory
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   map[string]*bucket{},
+		lastSweep: now(),
+	}
+}
+
+// sweepInterval is how often full buckets are removed, they are the same as new ones
+const sweepInterval = time.Minute
+
+// Take takes a token from the key bucket
+func (s *MemoryStore) Take(key string, limit Limit) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := now()
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	if current.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(current)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: current}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+current.Sub(b.last).Seconds()*rate)
+	b.last = current
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	b.full = current.Add(seconds((capacity - b.tokens) / rate))
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+
+	return result, nil
+}
+
+// Removes the buckets that would be full by now, each one refills at the rate
+// of its own limit
+func (s *MemoryStore) sweep(current time.Time) {
+	for key, b := range s.buckets {
+		if !current.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = current
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
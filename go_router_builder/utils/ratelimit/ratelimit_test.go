@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestTokenBucket(t *testing.T) {
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Per: 2 * time.Second}
+
+	result, _ := store.Take("a", limit)
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 1)
+	assert.Equal(t, result.Reset, time.Second)
+
+	store.Take("a", limit)
+	result, _ = store.Take("a", limit)
+	assert.Equal(t, result.Allowed, false)
+	assert.Equal(t, result.RetryAfter, time.Second)
+
+	result, _ = store.Take("b", limit)
+	assert.Equal(t, result.Allowed, true)
+
+	current = current.Add(time.Second)
+	result, _ = store.Take("a", limit)
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 0)
+}
+
+func TestSweep(t *testing.T) {
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	store := NewMemoryStore()
+	limit := Limit{Requests: 10, Per: time.Second}
+	store.Take("a", limit)
+
+	current = current.Add(sweepInterval)
+	store.Take("b", limit)
+
+	assert.Equal(t, len(store.buckets), 1)
+}
+
+func TestSweepKeepsStrictLimits(t *testing.T) {
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	store := NewMemoryStore()
+	strict := Limit{Requests: 1, Per: time.Hour}
+
+	store.Take("strict", strict)
+	result, _ := store.Take("strict", strict)
+	assert.Equal(t, result.Allowed, false)
+
+	current = current.Add(sweepInterval)
+	store.Take("loose", Limit{Requests: 1000, Per: time.Second})
+
+	result, _ = store.Take("strict", strict)
+	assert.Equal(t, result.Allowed, false)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileReporter reports events as json lines in a file, rotating it when it reaches maxSize
+type FileReporter struct {
+	mutex      *sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewFileReporter creates a rotating file reporter, keeping maxBackups old files
+func NewFileReporter(path string, maxSize int64, maxBackups int) (*FileReporter, error) {
+	r := &FileReporter{
+		mutex:      &sync.Mutex{},
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Report writes the event, rotating the file if needed
+func (r *FileReporter) Report(event Event) error {
+	line, err := encode(event)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := r.file.Write(line)
+	r.size += int64(written)
+	return err
+}
+
+// Close closes the current file
+func (r *FileReporter) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.file.Close()
+}
+
+func (r *FileReporter) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *FileReporter) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups < 1 {
+		if err := os.Remove(r.path); err != nil {
+			return err
+		}
+		return r.open()
+	}
+
+	for i := r.maxBackups - 1; i > 0; i-- {
+		backup := fmt.Sprintf("%s.%d", r.path, i)
+		if _, err := os.Stat(backup); err == nil {
+			if err := os.Rename(backup, fmt.Sprintf("%s.%d", r.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	return r.open()
+}
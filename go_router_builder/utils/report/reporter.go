@@ -0,0 +1,98 @@
+package report
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event is an error reported by the error middleware
+type Event struct {
+	Time      time.Time `json:"time"`
+	Status    int       `json:"status"`
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Message   string    `json:"error"`
+}
+
+// Reporter sends error events to some destination
+type Reporter interface {
+	Report(event Event) error
+}
+
+// ReporterFunc adapts a function as a Reporter
+type ReporterFunc func(event Event) error
+
+// Report calls f(event)
+func (f ReporterFunc) Report(event Event) error {
+	return f(event)
+}
+
+// Se pueden mockear en los tests
+var now = time.Now
+var random = rand.Float64
+
+// Sample reports a fraction of the events by status. Statuses without rate are reported
+// when they are server errors, client errors are mostly noise unless a rate asks for them
+func Sample(reporter Reporter, rates map[int]float64) Reporter {
+	return ReporterFunc(func(event Event) error {
+		rate, ok := rates[event.Status]
+		if !ok && event.Status < http.StatusInternalServerError {
+			return nil
+		}
+		if ok && random() >= rate {
+			return nil
+		}
+		return reporter.Report(event)
+	})
+}
+
+// Deduplicate reports identical events only once within the given window
+func Deduplicate(reporter Reporter, window time.Duration) Reporter {
+	mutex := &sync.Mutex{}
+	reported := map[string]time.Time{}
+
+	return ReporterFunc(func(event Event) error {
+		key := fingerprint(event)
+		current := now()
+
+		mutex.Lock()
+		for k, last := range reported {
+			if current.Sub(last) >= window {
+				delete(reported, k)
+			}
+		}
+		_, duplicated := reported[key]
+		if !duplicated {
+			reported[key] = current
+		}
+		mutex.Unlock()
+
+		if duplicated {
+			return nil
+		}
+		return reporter.Report(event)
+	})
+}
+
+// The request id is left out, the same error in other requests is a duplicate
+func fingerprint(event Event) string {
+	return strconv.Itoa(event.Status) + " " + event.Method + " " + event.Path + " " + event.Message
+}
+
+type logLine struct {
+	Level string `json:"level"`
+	Event
+}
+
+func encode(event Event) ([]byte, error) {
+	line, err := json.Marshal(logLine{"error", event})
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
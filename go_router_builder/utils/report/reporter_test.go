@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+type fakeReporter struct {
+	events []Event
+}
+
+func (r *fakeReporter) Report(event Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestSample(t *testing.T) {
+	defaultRandom := random
+	random = func() float64 { return 0.5 }
+	defer func() { random = defaultRandom }()
+
+	fake := &fakeReporter{}
+	reporter := Sample(fake, map[int]float64{400: 0.1, 404: 0.9, 503: 0.1})
+
+	reporter.Report(Event{Status: 400})
+	reporter.Report(Event{Status: 404})
+	reporter.Report(Event{Status: 422})
+	reporter.Report(Event{Status: 500})
+	reporter.Report(Event{Status: 503})
+
+	// 422 has no rate and is a client error, 503 is sampled out
+	assert.Equal(t, len(fake.events), 2)
+	assert.Equal(t, fake.events[0].Status, 404)
+	assert.Equal(t, fake.events[1].Status, 500)
+}
+
+func TestDeduplicate(t *testing.T) {
+	current := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultNow := now
+	now = func() time.Time { return current }
+	defer func() { now = defaultNow }()
+
+	fake := &fakeReporter{}
+	reporter := Deduplicate(fake, time.Minute)
+
+	reporter.Report(Event{Status: 500, Message: "Error"})
+	reporter.Report(Event{Status: 500, Message: "Error"})
+	reporter.Report(Event{Status: 500, Message: "Other"})
+	assert.Equal(t, len(fake.events), 2)
+
+	current = current.Add(time.Minute)
+	reporter.Report(Event{Status: 500, Message: "Error"})
+	assert.Equal(t, len(fake.events), 3)
+}
+
+func TestStdoutReporter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	reporter := &streamReporter{mutex: &sync.Mutex{}, writer: buffer}
+
+	reporter.Report(Event{
+		Time:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:  500,
+		Method:  "GET",
+		Path:    "/users/:id",
+		Message: "Error",
+	})
+
+	assert.Equal(t, buffer.String(),
+		"{\"level\":\"error\",\"time\":\"2020-01-01T00:00:00Z\",\"status\":500,\"method\":\"GET\",\"path\":\"/users/:id\",\"error\":\"Error\"}\n")
+}
+
+func TestFileReporterRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "errors.log")
+	reporter, err := NewFileReporter(path, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reporter.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := reporter.Report(Event{Status: 500, Message: "Error"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(path + "*")
+	assert.Equal(t, len(files), 3)
+
+	_, err = os.Stat(path + ".3")
+	assert.Equal(t, os.IsNotExist(err), true)
+}
@@ -0,0 +1,183 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nmarsollier/go_router_builder/utils/uuid"
+)
+
+// ErrQueueFull is returned when events arrive faster than they can be sent
+var ErrQueueFull = errors.New("sentry reporter queue is full")
+
+// SentryReporter sends events in background using the Sentry envelope protocol
+type SentryReporter struct {
+	dsn      string
+	key      string
+	endpoint string
+	client   *http.Client
+	mutex    *sync.RWMutex
+	closed   bool
+	events   chan Event
+	done     chan struct{}
+}
+
+// NewSentryReporter creates a reporter from a Sentry DSN, https://<key>@<host>/<project>
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project := path[slash+1:]
+	if u.User == nil || u.User.Username() == "" || project == "" {
+		return nil, fmt.Errorf("invalid sentry dsn %q", dsn)
+	}
+
+	r := &SentryReporter{
+		dsn:      dsn,
+		key:      u.User.Username(),
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path[:slash], project),
+		client:   &http.Client{Timeout: 5 * time.Second},
+		mutex:    &sync.RWMutex{},
+		events:   make(chan Event, 100),
+		done:     make(chan struct{}),
+	}
+
+	go r.send()
+	return r, nil
+}
+
+// Report queues the event to be sent
+func (r *SentryReporter) Report(event Event) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.closed {
+		return nil
+	}
+
+	select {
+	case r.events <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close sends the queued events and stops the reporter, events not sent when ctx is done are lost
+func (r *SentryReporter) Close(ctx context.Context) error {
+	r.mutex.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mutex.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *SentryReporter) send() {
+	defer close(r.done)
+
+	for event := range r.events {
+		if err := r.post(event); err != nil {
+			log.Printf("Error sending event to sentry : %s", err.Error())
+		}
+	}
+}
+
+func (r *SentryReporter) post(event Event) error {
+	body, err := r.envelope(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-sentry-envelope")
+	request.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=go_router_builder/1.0, sentry_key=%s", r.key,
+	))
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("sentry responded %d", response.StatusCode)
+	}
+	return nil
+}
+
+type sentryEvent struct {
+	EventID   string            `json:"event_id"`
+	Timestamp string            `json:"timestamp"`
+	Level     string            `json:"level"`
+	Platform  string            `json:"platform"`
+	Message   map[string]string `json:"message"`
+	Request   map[string]string `json:"request,omitempty"`
+	Tags      map[string]string `json:"tags"`
+}
+
+func (r *SentryReporter) envelope(event Event) ([]byte, error) {
+	eventID := strings.Replace(uuid.New(), "-", "", -1)
+
+	header, err := json.Marshal(map[string]string{
+		"event_id": eventID,
+		"dsn":      r.dsn,
+		"sent_at":  now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{"status": strconv.Itoa(event.Status)}
+	if event.RequestID != "" {
+		tags["request_id"] = event.RequestID
+	}
+
+	payload, err := json.Marshal(sentryEvent{
+		EventID:   eventID,
+		Timestamp: event.Time.UTC().Format(time.RFC3339),
+		Level:     "error",
+		Platform:  "go",
+		Message:   map[string]string{"formatted": event.Message},
+		Request:   map[string]string{"method": event.Method, "url": event.Path},
+		Tags:      tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := json.Marshal(map[string]interface{}{
+		"type":   "event",
+		"length": len(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.Join([][]byte{header, item, payload}, []byte("\n")), nil
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestSentryReporter(t *testing.T) {
+	var paths, auths []string
+	var bodies [][]byte
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("X-Sentry-Auth"))
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer stub.Close()
+
+	dsn := strings.Replace(stub.URL, "http://", "http://public@", 1) + "/42"
+	reporter, err := NewSentryReporter(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reporter.Report(Event{Status: 500, Method: "GET", Path: "/users/:id", RequestID: "req-1", Message: "Error Test"})
+	reporter.Close(context.Background())
+
+	assert.Equal(t, len(bodies), 1)
+	assert.Equal(t, paths[0], "/api/42/envelope/")
+	assert.Equal(t, strings.Contains(auths[0], "sentry_key=public"), true)
+
+	lines := bytes.Split(bodies[0], []byte("\n"))
+	assert.Equal(t, len(lines), 3)
+
+	item := map[string]interface{}{}
+	json.Unmarshal(lines[1], &item)
+	assert.Equal(t, item["type"], "event")
+	assert.Equal(t, int(item["length"].(float64)), len(lines[2]))
+
+	event := sentryEvent{}
+	json.Unmarshal(lines[2], &event)
+	assert.Equal(t, event.Message["formatted"], "Error Test")
+	assert.Equal(t, event.Tags["status"], "500")
+	assert.Equal(t, event.Tags["request_id"], "req-1")
+}
+
+func TestSentryInvalidDsn(t *testing.T) {
+	_, err := NewSentryReporter("http://sentry.local/42")
+	assert.NotEqual(t, err, nil)
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+type streamReporter struct {
+	mutex  *sync.Mutex
+	writer io.Writer
+}
+
+// NewStdoutReporter reports events as json lines in stdout
+func NewStdoutReporter() Reporter {
+	return &streamReporter{
+		mutex:  &sync.Mutex{},
+		writer: os.Stdout,
+	}
+}
+
+func (r *streamReporter) Report(event Event) error {
+	line, err := encode(event)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, err = r.writer.Write(line)
+	return err
+}
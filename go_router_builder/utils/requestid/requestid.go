@@ -0,0 +1,38 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/nmarsollier/go_router_builder/utils/uuid"
+)
+
+// Header is the header that carries the request id
+const Header = "X-Request-ID"
+
+type key struct{}
+
+// NewContext returns a copy of ctx that carries id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext is the request id of ctx, empty when there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
+
+var valid = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Resolve is the id sent by the client, or a new one when it is missing or
+// could be used to forge log lines
+func Resolve(received string) string {
+	if valid.MatchString(received) {
+		return received
+	}
+	return uuid.New()
+}
@@ -0,0 +1,64 @@
+package shutdown
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+type hook struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Hooks are functions to run when the server stops, like flushing caches or closing DAOs
+type Hooks struct {
+	mutex    sync.Mutex
+	hooks    []hook
+	draining int32
+}
+
+// Drain marks the server as draining, it stops being ready for new requests
+func (h *Hooks) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Draining tells if the server is shutting down
+func (h *Hooks) Draining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// Add registers a hook, hooks run in reverse order of registration
+func (h *Hooks) Add(name string, run func(ctx context.Context) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.hooks = append(h.hooks, hook{name, run})
+}
+
+// Run runs the registered hooks in reverse order, errors are logged and do not stop the rest.
+// It returns when ctx is done, without waiting for the running hook or running the next ones
+func (h *Hooks) Run(ctx context.Context) {
+	h.mutex.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mutex.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		done := make(chan error, 1)
+		go func(hook hook) {
+			done <- hook.run(ctx)
+		}(hooks[i])
+
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Printf("Shutdown hook %s failed : %s", hooks[i].name, err.Error())
+			}
+		case <-ctx.Done():
+			log.Printf("Shutdown hook %s did not finish, %d hooks not run : %s", hooks[i].name, i, ctx.Err().Error())
+			return
+		}
+	}
+}